@@ -0,0 +1,63 @@
+package main
+
+import "debateData/debatedata"
+
+// aggregateDebates merges every debate into one, with a single entry per candidate holding the sum of their issue
+// counts across all of the debates they appeared in. Candidates keep the order in which they first appear; an issue
+// missing from some of a candidate's debates simply contributes nothing there.
+func aggregateDebates(debates []Debate) Debate {
+
+	var merged Debate
+	var candidateIndex = make(map[string]int)
+
+	for _, d := range debates {
+		for _, candidate := range d.Candidates {
+			idx, exists := candidateIndex[candidate.Name]
+
+			if !exists {
+				var aggregate = Candidate{Name: candidate.Name, IssueCount: make(map[string]int)}
+
+				if candidate.WeightedCount != nil {
+					aggregate.WeightedCount = make(map[string]float64)
+				}
+
+				idx = len(merged.Candidates)
+				candidateIndex[candidate.Name] = idx
+				merged.Candidates = append(merged.Candidates, aggregate)
+			}
+
+			for issue, count := range candidate.IssueCount {
+				merged.Candidates[idx].IssueCount[issue] += count
+			}
+
+			for issue, count := range candidate.WeightedCount {
+				merged.Candidates[idx].WeightedCount[issue] += count
+			}
+		}
+	}
+
+	return merged
+}
+
+// summarizeAggregate builds the summary matrix over the aggregated debates: one row per candidate and the Total row,
+// with no Date (or group) column since the rows no longer belong to a single debate
+func summarizeAggregate(debates *[]Debate, opts debatedata.SummaryOptions, sortMode string) ([][]string, error) {
+
+	var aggregated = []Debate{aggregateDebates(*debates)}
+
+	sortCandidates(&aggregated, sortMode)
+
+	opts.GroupColumn = ""
+
+	summary, err := debatedata.Summarize(&aggregated, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for k, row := range summary {
+		summary[k] = row[1:]
+	}
+
+	return summary, nil
+}
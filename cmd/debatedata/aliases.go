@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"debateData/debatedata"
+)
+
+// readAliases reads a JSON file mapping canonical issue or candidate names to lists of variants, e.g.
+// {"Healthcare": ["health care", "Health Care"]}, and returns a lookup from each variant (and the canonical name
+// itself), keyed by AliasKey, to its canonical form. Files ending in .yaml or .yml hold the same mapping in YAML. A
+// variant listed under two canonical names is an error.
+func readAliases(fileName string) (map[string]string, error) {
+
+	data, err := os.ReadFile(fileName)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read alias file: %v", err)
+	}
+
+	var canonicals map[string][]string
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &canonicals)
+	default:
+		err = json.Unmarshal(data, &canonicals)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not decode alias file '%v': %v", fileName, err)
+	}
+
+	var aliases = make(map[string]string)
+
+	for canonical, variants := range canonicals {
+		canonical = strings.Join(strings.Fields(canonical), " ")
+
+		for _, variant := range append([]string{canonical}, variants...) {
+			key := debatedata.AliasKey(variant)
+
+			if existing, exists := aliases[key]; exists && existing != canonical {
+				return nil, fmt.Errorf("alias file '%v': '%v' is listed under both '%v' and '%v'", fileName, variant, existing, canonical)
+			}
+
+			aliases[key] = canonical
+		}
+	}
+
+	return aliases, nil
+}
+
+// aliasReporter returns a ParseOptions.AliasApplied callback that notes each candidate alias the first time it is
+// applied. Inputs may be parsed concurrently, so the callback is safe to call from several goroutines.
+func aliasReporter() func(variant, canonical string) {
+
+	var mu sync.Mutex
+	var reported = make(map[string]bool)
+
+	return func(variant, canonical string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !reported[variant] {
+			reported[variant] = true
+			notef("merged candidate '%v' into '%v'", variant, canonical)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// archiveConfigName is the archive entry holding the job configuration
+const archiveConfigName = "config.json"
+
+// archiveConfig is the job configuration stored in an archive. Input names the CSV entry to summarize and Flags
+// holds command-line flag values by flag name; repeatable flags take a list.
+type archiveConfig struct {
+	Input string                 `json:"input"`
+	Flags map[string]interface{} `json:"flags"`
+}
+
+// readArchiveConfig opens a report bundle and returns its configuration. A missing config.json is treated as an
+// empty config.
+func readArchiveConfig(fileName string) (*archiveConfig, error) {
+
+	r, err := zip.OpenReader(fileName)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not open archive: %v", err)
+	}
+
+	defer func(r *zip.ReadCloser) {
+		err := r.Close()
+		if err != nil {
+
+		}
+	}(r)
+
+	var config archiveConfig
+
+	for _, f := range r.File {
+		if f.Name == archiveConfigName {
+			if err = readArchiveEntry(f, func(rc io.Reader) error { return json.NewDecoder(rc).Decode(&config) }); err != nil {
+				return nil, fmt.Errorf("could not read archive config: %v", err)
+			}
+		}
+	}
+
+	return &config, nil
+}
+
+// readArchive opens a report bundle and returns the records of its CSV entry. The entry is the given name when set,
+// otherwise the config's input, otherwise the archive's only .csv entry. The dialect should already reflect the
+// flags the config sets.
+func readArchive(fileName string, entry string, config *archiveConfig, dialect csvDialect) ([][]string, error) {
+
+	r, err := zip.OpenReader(fileName)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not open archive: %v", err)
+	}
+
+	defer func(r *zip.ReadCloser) {
+		err := r.Close()
+		if err != nil {
+
+		}
+	}(r)
+
+	var csvEntries []*zip.File
+
+	for _, f := range r.File {
+		if strings.EqualFold(path.Ext(f.Name), ".csv") {
+			csvEntries = append(csvEntries, f)
+		}
+	}
+
+	if entry == "" {
+		entry = config.Input
+	}
+
+	var csvEntry *zip.File
+
+	for _, f := range csvEntries {
+		if entry == "" || f.Name == entry {
+			if csvEntry != nil {
+				return nil, fmt.Errorf("archive '%v' contains several CSV files; name one with -archive-entry or the config's input", fileName)
+			}
+			csvEntry = f
+		}
+	}
+
+	if csvEntry == nil {
+		return nil, fmt.Errorf("archive '%v' has no CSV entry '%v'", fileName, entry)
+	}
+
+	var records [][]string
+
+	err = readArchiveEntry(csvEntry, func(rc io.Reader) error {
+		var err error
+		records, err = dialect.newReader(rc).ReadAll()
+		return err
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read csv '%v' from archive: %v", csvEntry.Name, err)
+	}
+
+	return records, nil
+}
+
+// readArchiveEntry opens an archive entry, hands it to read and closes it again
+func readArchiveEntry(f *zip.File, read func(io.Reader) error) error {
+
+	rc, err := f.Open()
+
+	if err != nil {
+		return err
+	}
+
+	defer func(rc io.ReadCloser) {
+		err := rc.Close()
+		if err != nil {
+
+		}
+	}(rc)
+
+	return read(rc)
+}
+
+// applyArchiveFlags sets the flags named in an archive config. Flags given explicitly on the command line win over
+// the archive's values.
+func applyArchiveFlags(config *archiveConfig) error {
+
+	var explicit = make(map[string]bool)
+
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	for name, value := range config.Flags {
+		if explicit[name] {
+			continue
+		}
+
+		if flag.Lookup(name) == nil {
+			return fmt.Errorf("archive config sets unknown flag '%v'", name)
+		}
+
+		values, repeated := value.([]interface{})
+
+		if !repeated {
+			values = []interface{}{value}
+		}
+
+		for _, v := range values {
+			if err := flag.Set(name, fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("archive config flag '%v': %v", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeArchive writes a new archive containing every entry of the source archive plus the given files, each stored
+// under its base name. Added files replace source entries with the same name.
+func writeArchive(source string, target string, files ...string) error {
+
+	r, err := zip.OpenReader(source)
+
+	if err != nil {
+		return fmt.Errorf("could not open archive: %v", err)
+	}
+
+	defer func(r *zip.ReadCloser) {
+		err := r.Close()
+		if err != nil {
+
+		}
+	}(r)
+
+	f, err := os.Create(target)
+
+	if err != nil {
+		return fmt.Errorf("could not create archive: %v", err)
+	}
+
+	defer func(f *os.File) {
+		err := f.Close()
+		if err != nil {
+
+		}
+	}(f)
+
+	w := zip.NewWriter(f)
+
+	var added = make(map[string]bool)
+
+	for _, file := range files {
+		added[filepath.Base(file)] = true
+	}
+
+	for _, entry := range r.File {
+		if added[entry.Name] {
+			continue
+		}
+
+		if err = w.Copy(entry); err != nil {
+			return fmt.Errorf("could not copy '%v' into archive: %v", entry.Name, err)
+		}
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+
+		if err != nil {
+			return fmt.Errorf("could not read '%v' for archiving: %v", file, err)
+		}
+
+		entry, err := w.Create(filepath.Base(file))
+
+		if err != nil {
+			return fmt.Errorf("could not add '%v' to archive: %v", file, err)
+		}
+
+		if _, err = entry.Write(data); err != nil {
+			return fmt.Errorf("could not write '%v' to archive: %v", file, err)
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("could not finish archive: %v", err)
+	}
+
+	return nil
+}
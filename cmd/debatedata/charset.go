@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+
+	"debateData/debatedata"
+)
+
+// lookupEncoding resolves an IANA charset name or alias (e.g. ISO-8859-1, latin1, windows-1252). UTF-8 resolves to
+// nil, since that is what the csv package reads and writes natively.
+func lookupEncoding(name string) (encoding.Encoding, error) {
+
+	enc, err := ianaindex.IANA.Encoding(name)
+
+	if err == nil && enc == nil {
+		err = fmt.Errorf("unsupported")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unknown charset '%v': %v", name, err)
+	}
+
+	if enc == unicode.UTF8 {
+		return nil, nil
+	}
+
+	return enc, nil
+}
+
+// encodeCells checks that every cell of data can be represented in enc. With transliterate, characters it cannot
+// represent are replaced in place by their accent-folded form, or by "?" when that doesn't fit either; without it the
+// first such cell is an error.
+func encodeCells(data [][]string, enc encoding.Encoding, transliterate bool) error {
+
+	encoder := enc.NewEncoder()
+
+	for rowNum, row := range data {
+		for colNum, cell := range row {
+			if _, err := encoder.String(cell); err == nil {
+				continue
+			}
+
+			if !transliterate {
+				return fmt.Errorf("'%v' at row %d, column %d cannot be represented in %v (pass -transliterate to substitute)", cell, rowNum, colNum, enc)
+			}
+
+			var b strings.Builder
+
+			for _, r := range cell {
+				switch {
+				case fits(encoder, string(r)):
+					b.WriteRune(r)
+				case fits(encoder, debatedata.FoldAccents(string(r))):
+					b.WriteString(debatedata.FoldAccents(string(r)))
+				default:
+					b.WriteRune('?')
+				}
+			}
+
+			row[colNum] = b.String()
+		}
+	}
+
+	return nil
+}
+
+// fits reports whether val can be encoded without loss
+func fits(encoder *encoding.Encoder, val string) bool {
+	_, err := encoder.String(val)
+
+	return err == nil
+}
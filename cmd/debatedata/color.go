@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// Color modes for -color
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// ANSI escape sequences used on the console
+const (
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+	ansiReset  = "\x1b[0m"
+)
+
+// stderrColor is set when console messages on stderr are colorized. Output files are never colorized.
+var stderrColor bool
+
+// setColorMode resolves a -color mode. auto colorizes only when stderr is a terminal and NO_COLOR is not set.
+func setColorMode(mode string) error {
+	switch mode {
+	case colorAlways:
+		stderrColor = true
+	case colorNever:
+		stderrColor = false
+	case colorAuto:
+		info, err := os.Stderr.Stat()
+		_, noColor := os.LookupEnv("NO_COLOR")
+		stderrColor = err == nil && info.Mode()&os.ModeCharDevice != 0 && !noColor && os.Getenv("TERM") != "dumb"
+	default:
+		return fmt.Errorf("invalid -color value '%v': expected auto, always or never", mode)
+	}
+
+	return nil
+}
+
+// colorize wraps text in an ANSI color when stderr colorization is on
+func colorize(color string, text string) string {
+	if !stderrColor {
+		return text
+	}
+
+	return color + text + ansiReset
+}
+
+// warnf prints a warning to stderr
+func warnf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%v %v\n", colorize(ansiYellow, "warning:"), fmt.Sprintf(format, args...))
+}
+
+// notef prints an informational note to stderr
+func notef(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%v %v\n", colorize(ansiCyan, "note:"), fmt.Sprintf(format, args...))
+}
+
+// printError prints an error that ends the run to stderr
+func printError(err error) {
+	fmt.Fprintln(os.Stderr, colorize(ansiRed, err.Error()))
+}
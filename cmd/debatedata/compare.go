@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"debateData/debatedata"
+)
+
+// parseComparePair parses a -compare value of the form A,B into the two candidate names
+func parseComparePair(val string) (string, string, error) {
+
+	parts := strings.Split(val, ",")
+
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return "", "", fmt.Errorf("invalid -compare value '%v': expected two candidate names as A,B", val)
+	}
+
+	return debatedata.SanitizeColumnName(parts[0]), debatedata.SanitizeColumnName(parts[1]), nil
+}
+
+// summarizeComparison builds a head-to-head table of two candidates' issue counts summed across all debates, with
+// the difference (first minus second) for each issue. Rows are sorted by the absolute difference, largest first,
+// with ties broken alphabetically. Candidate names match case-insensitively.
+func summarizeComparison(debates *[]Debate, first string, second string) ([][]string, error) {
+
+	var totals = [2]map[string]float64{make(map[string]float64), make(map[string]float64)}
+	var names = [2]string{}
+	var issues = make(map[string]interface{})
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			for k, want := range []string{first, second} {
+				if !strings.EqualFold(candidate.Name, want) {
+					continue
+				}
+
+				names[k] = candidate.Name
+
+				for issue := range candidate.IssueCount {
+					totals[k][issue] += candidate.Score(issue)
+					issues[issue] = nil
+				}
+			}
+		}
+	}
+
+	for k, want := range []string{first, second} {
+		if names[k] == "" {
+			return nil, fmt.Errorf("candidate '%v' not found in the input", want)
+		}
+	}
+
+	var sortedIssues []string
+
+	for issue := range issues {
+		sortedIssues = append(sortedIssues, issue)
+	}
+
+	sort.Slice(sortedIssues, func(i, j int) bool {
+		di := math.Abs(totals[0][sortedIssues[i]] - totals[1][sortedIssues[i]])
+		dj := math.Abs(totals[0][sortedIssues[j]] - totals[1][sortedIssues[j]])
+
+		if di != dj {
+			return di > dj
+		}
+
+		return sortedIssues[i] < sortedIssues[j]
+	})
+
+	var rows = [][]string{{"Issue", names[0], names[1], "Difference"}}
+
+	for _, issue := range sortedIssues {
+		rows = append(rows, []string{
+			issue,
+			debatedata.FormatWeighted(totals[0][issue]),
+			debatedata.FormatWeighted(totals[1][issue]),
+			debatedata.FormatWeighted(totals[0][issue] - totals[1][issue]),
+		})
+	}
+
+	return rows, nil
+}
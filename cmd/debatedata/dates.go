@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the date formats accepted in the source data, tried in order
+var dateLayouts = []string{
+	"1/2/2006",
+	"2006-01-02",
+	"Jan 2 2006",
+	"Jan 2, 2006",
+	"January 2 2006",
+	"January 2, 2006",
+	"1/2/2006 15:04",
+	"1/2/2006 3:04 PM",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+}
+
+// Debate orders control the order of the debates in the output
+const (
+	// debateOrderInput keeps debates in the order they were read
+	debateOrderInput = "input"
+	// debateOrderDate sorts debates chronologically
+	debateOrderDate = "date"
+)
+
+// addDateLayouts puts user-supplied Go time layouts, such as 02.01.2006, ahead of the built-in ones. A layout that
+// holds no date or time elements, or can't read back what it formats, is an error.
+func addDateLayouts(layouts []string) error {
+
+	// Any date other than Go's reference date itself shows whether a layout has elements to fill in
+	sample := time.Date(2021, time.November, 23, 13, 45, 37, 0, time.UTC)
+
+	for _, layout := range layouts {
+		formatted := sample.Format(layout)
+
+		if formatted == layout {
+			return fmt.Errorf("date layout '%v' has no date elements; write it with Go's reference date, e.g. 02.01.2006", layout)
+		}
+
+		if _, err := time.Parse(layout, formatted); err != nil {
+			return fmt.Errorf("date layout '%v' can't be parsed back: %v", layout, err)
+		}
+	}
+
+	dateLayouts = append(append([]string{}, layouts...), dateLayouts...)
+
+	return nil
+}
+
+// parseDate parses a debate date using the first layout in dateLayouts that matches. Values without a zone are read
+// as local time in loc (UTC when loc is nil); values with an explicit offset are converted to loc, so a late-evening
+// debate lands on the calendar day it had in that zone.
+func parseDate(value string, loc *time.Location) (time.Time, error) {
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	value = strings.TrimSpace(value)
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.In(loc), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("'%v' does not match any known date layout (add one with -date-layout)", value)
+}
+
+// sortDebatesByDate orders debates chronologically. Debates on the same date keep their input order. A date that
+// doesn't parse is an error naming the value, since there is no place in the order to put it.
+func sortDebatesByDate(debates *[]Debate, loc *time.Location) error {
+
+	var dates = make([]time.Time, len(*debates))
+
+	for k, debate := range *debates {
+		t, err := parseDate(debate.Date, loc)
+
+		if err != nil {
+			return &DataError{Row: -1, Column: -1, Kind: InvalidDate, Message: err.Error()}
+		}
+
+		dates[k] = t
+	}
+
+	// Sort an index so the parsed dates and debates move together
+	var order = make([]int, len(*debates))
+
+	for k := range order {
+		order[k] = k
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return dates[order[i]].Before(dates[order[j]])
+	})
+
+	var sorted = make([]Debate, len(*debates))
+
+	for k, idx := range order {
+		sorted[k] = (*debates)[idx]
+	}
+
+	*debates = sorted
+
+	return nil
+}
+
+// debatesInRange returns the debates whose calendar date in loc falls within from and to, inclusive. A zero from or
+// to leaves that end of the range open. A debate date that doesn't parse is an error naming the value.
+func debatesInRange(debates []Debate, from time.Time, to time.Time, loc *time.Location) ([]Debate, error) {
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var kept = make([]Debate, 0, len(debates))
+
+	for _, debate := range debates {
+		t, err := parseDate(debate.Date, loc)
+
+		if err != nil {
+			return nil, &DataError{Row: -1, Column: -1, Kind: InvalidDate, Message: err.Error()}
+		}
+
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+
+		if (!from.IsZero() && day.Before(from)) || (!to.IsZero() && day.After(to)) {
+			continue
+		}
+
+		kept = append(kept, debate)
+	}
+
+	return kept, nil
+}
+
+// isoDate is the layout debate dates are normalized to
+const isoDate = "2006-01-02"
+
+// normalizeDates checks that every debate date parses with one of the dateLayouts and, when normalize is set, rewrites
+// it as an ISO YYYY-MM-DD date in loc. An unparseable date is an error naming the value when strict is set;
+// otherwise it is left as it is with a warning.
+func normalizeDates(debates []Debate, loc *time.Location, normalize bool, strict bool) error {
+
+	for k := range debates {
+		t, err := parseDate(debates[k].Date, loc)
+
+		if err != nil {
+			dataErr := &DataError{Row: -1, Column: -1, Kind: InvalidDate, Message: err.Error()}
+
+			if strict {
+				return dataErr
+			}
+
+			warnf("%v", dataErr)
+			continue
+		}
+
+		if normalize {
+			debates[k].Date = t.Format(isoDate)
+		}
+	}
+
+	return nil
+}
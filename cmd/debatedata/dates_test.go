@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestNormalizeDates checks each supported date style against its ISO form, and that an unparseable date fails under
+// strict mode but passes through unchanged otherwise
+func TestNormalizeDates(t *testing.T) {
+
+	tests := []struct {
+		date string
+		want string
+		// invalid marks a date that matches no layout
+		invalid bool
+	}{
+		{date: "11/5/2020", want: "2020-11-05"},
+		{date: "2020-11-05", want: "2020-11-05"},
+		{date: "Nov 5 2020", want: "2020-11-05"},
+		{date: "November 5, 2020", want: "2020-11-05"},
+		{date: " 11/5/2020 ", want: "2020-11-05"},
+		{date: "the fifth of November", want: "the fifth of November", invalid: true},
+	}
+
+	for _, test := range tests {
+		debates := []Debate{{Date: test.date}}
+
+		err := normalizeDates(debates, nil, true, true)
+
+		var dataErr *DataError
+
+		if test.invalid {
+			if !errors.As(err, &dataErr) || dataErr.Kind != InvalidDate {
+				t.Errorf("normalizeDates(%q) error = %v, want an invalid date DataError", test.date, err)
+			}
+		} else if err != nil {
+			t.Errorf("normalizeDates(%q): %v", test.date, err)
+		}
+
+		if err == nil && debates[0].Date != test.want {
+			t.Errorf("normalizeDates(%q) = %q, want %q", test.date, debates[0].Date, test.want)
+		}
+
+		// Without strict mode a bad date is only a warning and stays as it was
+		debates = []Debate{{Date: test.date}}
+
+		if err = normalizeDates(debates, nil, true, false); err != nil {
+			t.Errorf("normalizeDates(%q) without strict: %v", test.date, err)
+		}
+
+		if debates[0].Date != test.want {
+			t.Errorf("normalizeDates(%q) without strict = %q, want %q", test.date, debates[0].Date, test.want)
+		}
+	}
+}
+
+// TestSortDebatesByDate checks that debates sort chronologically across date styles with ties in input order, and that
+// an unparseable date fails the sort with an invalid date error naming it, leaving the debates as they were
+func TestSortDebatesByDate(t *testing.T) {
+
+	debates := []Debate{
+		{Date: "2021-03-01", Group: "a"},
+		{Date: "Jan 5 2021", Group: "b"},
+		{Date: "3/1/2021", Group: "c"},
+		{Date: "2/1/2021", Group: "d"},
+	}
+
+	if err := sortDebatesByDate(&debates, nil); err != nil {
+		t.Fatalf("sortDebatesByDate: %v", err)
+	}
+
+	var got string
+
+	for _, debate := range debates {
+		got += debate.Group
+	}
+
+	if got != "bdac" {
+		t.Errorf("debate order = %q, want %q", got, "bdac")
+	}
+
+	debates = []Debate{{Date: "2/1/2021"}, {Date: "TBD"}, {Date: "1/1/2021"}}
+
+	err := sortDebatesByDate(&debates, nil)
+	var dataErr *DataError
+
+	if !errors.As(err, &dataErr) || dataErr.Kind != InvalidDate || dataErr.Row != -1 || !strings.Contains(dataErr.Message, "'TBD'") {
+		t.Fatalf("sortDebatesByDate error = %v, want an invalid date DataError for 'TBD' with no row", err)
+	}
+
+	if debates[0].Date != "2/1/2021" {
+		t.Errorf("debates were reordered despite the error: %+v", debates)
+	}
+}
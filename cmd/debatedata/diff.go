@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"debateData/debatedata"
+)
+
+// diffSummaries compares two summary matrices produced by debatedata.Summarize and returns a matrix of the same shape holding
+// current minus previous for every cell. Rows are matched on their Date and Candidate columns and issue columns on
+// their header, so reordered or added rows and issues line up; anything missing on one side counts as 0. Rows and
+// columns follow the current summary, followed by any that only exist in the previous one. Cells whose absolute
+// change is below threshold are left blank.
+func diffSummaries(previous [][]string, current [][]string, threshold float64) ([][]string, error) {
+
+	prevValues, prevRows, prevCols, err := indexSummary(previous)
+
+	if err != nil {
+		return nil, fmt.Errorf("previous summary: %w", err)
+	}
+
+	currValues, currRows, currCols, err := indexSummary(current)
+
+	if err != nil {
+		return nil, fmt.Errorf("current summary: %w", err)
+	}
+
+	columns := appendMissing(currCols, prevCols)
+
+	// Each summary ends with its Total row, which stays last after the rows found only in the previous summary
+	currBody, currTotal := splitLast(currRows)
+	prevBody, prevTotal := splitLast(prevRows)
+	rowKeys := appendMissing(appendMissing(currBody, prevBody), append(currTotal, prevTotal...))
+
+	var header = append([]string{current[0][0], current[0][1]}, columns...)
+	var rows = [][]string{header}
+
+	for _, key := range rowKeys {
+		var row = make([]string, len(header))
+
+		row[0], row[1] = splitRowKey(key)
+
+		for k, column := range columns {
+			delta := currValues[key][column] - prevValues[key][column]
+
+			if math.Abs(delta) < threshold {
+				continue
+			}
+
+			row[k+2] = formatDelta(delta)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// indexSummary maps a summary's cells by row key and issue column, and returns the row keys and issue columns in
+// their original order
+func indexSummary(summary [][]string) (map[string]map[string]float64, []string, []string, error) {
+
+	var values = make(map[string]map[string]float64)
+	var rowKeys []string
+
+	if len(summary) == 0 || len(summary[0]) < 2 {
+		return nil, nil, nil, fmt.Errorf("summary has no Date and Candidate columns")
+	}
+
+	columns := summary[0][2:]
+
+	for rowNum, row := range summary[1:] {
+		if len(row) != len(summary[0]) {
+			return nil, nil, nil, fmt.Errorf("row %d has %d columns, expected %d", rowNum+1, len(row), len(summary[0]))
+		}
+
+		key := rowKey(row[0], row[1])
+		rowKeys = append(rowKeys, key)
+		values[key] = make(map[string]float64)
+
+		for k, column := range columns {
+			if row[k+2] == "" {
+				continue
+			}
+
+			val, err := strconv.ParseFloat(row[k+2], 64)
+
+			if err != nil {
+				return nil, nil, nil, &DataError{
+					Row:     rowNum + 1,
+					Column:  k + 2,
+					Kind:    InvalidCount,
+					Message: fmt.Sprintf("'%v' is not a valid issue count", row[k+2]),
+				}
+			}
+
+			values[key][column] = val
+		}
+	}
+
+	return values, rowKeys, columns, nil
+}
+
+// rowKey joins a row's Date and Candidate into a single lookup key
+func rowKey(date string, candidate string) string {
+	return date + "\x00" + candidate
+}
+
+// splitRowKey is the inverse of rowKey
+func splitRowKey(key string) (string, string) {
+	parts := strings.SplitN(key, "\x00", 2)
+
+	return parts[0], parts[1]
+}
+
+// appendMissing returns first followed by the elements of second that are not in first
+func appendMissing(first []string, second []string) []string {
+
+	var seen = make(map[string]bool)
+	var merged = append([]string{}, first...)
+
+	for _, v := range first {
+		seen[v] = true
+	}
+
+	for _, v := range second {
+		if !seen[v] {
+			merged = append(merged, v)
+			seen[v] = true
+		}
+	}
+
+	return merged
+}
+
+// splitLast splits off the last of keys, returning the rest and a slice holding just the last key, or nothing when
+// keys is empty
+func splitLast(keys []string) ([]string, []string) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	return keys[:len(keys)-1], keys[len(keys)-1:]
+}
+
+// formatDelta renders a change with an explicit sign so increases are easy to tell from decreases
+func formatDelta(delta float64) string {
+	if delta > 0 {
+		return "+" + debatedata.FormatWeighted(delta)
+	}
+
+	return debatedata.FormatWeighted(delta)
+}
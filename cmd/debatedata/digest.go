@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"debateData/debatedata"
+)
+
+// issueTotal is an issue's count summed over every candidate and debate
+type issueTotal struct {
+	issue string
+	total int
+}
+
+// rankIssues sums each issue's IssueCount over every candidate in every debate and returns the issues ordered by
+// that total, descending, with ties broken alphabetically
+func rankIssues(debates *[]Debate) []issueTotal {
+
+	var totals = make(map[string]int)
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			for issue, count := range candidate.IssueCount {
+				totals[issue] += count
+			}
+		}
+	}
+
+	var ranked []issueTotal
+
+	for _, issue := range debatedata.GetIssues(debates) {
+		ranked = append(ranked, issueTotal{issue: issue, total: totals[issue]})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].total > ranked[j].total
+	})
+
+	return ranked
+}
+
+// printDigest writes an at-a-glance profile of the data: the n most- and least-discussed issues overall, with their
+// total mentions and share of all mentions. Ties are broken alphabetically. It is meant for the console, so issue
+// names are colorized when stderr colorization is on.
+func printDigest(w io.Writer, debates *[]Debate, n int) {
+
+	ranked := rankIssues(debates)
+
+	var mentions = 0
+
+	for _, t := range ranked {
+		mentions += t.total
+	}
+
+	if n > len(ranked) {
+		n = len(ranked)
+	}
+
+	fmt.Fprintf(w, "%d debates, %d issues, %d mentions\n", len(*debates), len(ranked), mentions)
+
+	printList := func(title string, issues []issueTotal) {
+		fmt.Fprintf(w, "%v:\n", title)
+
+		for _, t := range issues {
+			var share = 0.0
+
+			if mentions != 0 {
+				share = float64(t.total) / float64(mentions) * 100
+			}
+
+			fmt.Fprintf(w, "  %v  %d (%.1f%%)\n", colorize(ansiCyan, t.issue), t.total, share)
+		}
+	}
+
+	printList(fmt.Sprintf("top %d issues", n), ranked[:n])
+
+	// The least-discussed list puts the rarest issue first, again breaking ties alphabetically
+	var least = append([]issueTotal{}, ranked...)
+
+	sort.SliceStable(least, func(i, j int) bool {
+		return least[i].total < least[j].total
+	})
+
+	least = least[:n]
+
+	printList(fmt.Sprintf("least-discussed %d issues", n), least)
+}
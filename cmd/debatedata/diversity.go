@@ -0,0 +1,46 @@
+package main
+
+import (
+	"math"
+	"strconv"
+)
+
+// summarizeDiversity reports how broadly each candidate spread their attention in each debate: the number of
+// distinct issues they raised and the Shannon entropy (in bits) of their issue distribution. Higher entropy means
+// mentions were spread more evenly across more issues; a candidate who raised a single issue scores 0.
+func summarizeDiversity(debates *[]Debate) [][]string {
+
+	var rows = [][]string{{"Date", "Candidate", "DistinctIssues", "Entropy"}}
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			var distinct = 0
+			var total = 0.0
+
+			for issue := range candidate.IssueCount {
+				if score := candidate.Score(issue); score > 0 {
+					distinct++
+					total += score
+				}
+			}
+
+			var entropy = 0.0
+
+			for issue := range candidate.IssueCount {
+				if score := candidate.Score(issue); score > 0 {
+					p := score / total
+					entropy -= p * math.Log2(p)
+				}
+			}
+
+			rows = append(rows, []string{
+				debate.Date,
+				candidate.Name,
+				strconv.Itoa(distinct),
+				strconv.FormatFloat(entropy, 'f', 3, 64),
+			})
+		}
+	}
+
+	return rows
+}
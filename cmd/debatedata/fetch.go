@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// retryPolicy controls how URL inputs are re-fetched after transient failures
+type retryPolicy struct {
+	// retries is the number of extra attempts after the first one fails
+	retries int
+	// delay is the wait before the first retry; it doubles for every retry after that
+	delay time.Duration
+	// log receives a line per attempt when set
+	log io.Writer
+}
+
+// isURL reports whether an input names an HTTP(S) URL rather than a local file
+func isURL(input string) bool {
+	return strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://")
+}
+
+// fetchCsv downloads and parses a CSV from a URL. Network errors and 5xx responses are retried with exponential
+// backoff according to the policy; other responses other than 200 fail immediately.
+func fetchCsv(url string, dialect csvDialect, policy retryPolicy) ([][]string, error) {
+
+	var body []byte
+	var err error
+	var delay = policy.delay
+
+	for attempt := 0; attempt <= policy.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		var retry bool
+		body, retry, err = fetch(url)
+
+		if policy.log != nil {
+			if err != nil {
+				fmt.Fprintf(policy.log, "fetch %v: attempt %d of %d failed: %v\n", url, attempt+1, policy.retries+1, err)
+			} else {
+				fmt.Fprintf(policy.log, "fetch %v: attempt %d of %d succeeded\n", url, attempt+1, policy.retries+1)
+			}
+		}
+
+		if err == nil || !retry {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch csv: %v", err)
+	}
+
+	records, err := dialect.newReader(bytes.NewReader(body)).ReadAll()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read csv: %v", err)
+	}
+
+	return records, nil
+}
+
+// fetch makes a single GET request. It reports whether a failure is transient and worth retrying.
+func fetch(url string) ([]byte, bool, error) {
+
+	resp, err := http.Get(url)
+
+	if err != nil {
+		return nil, true, err
+	}
+
+	defer func(body io.ReadCloser) {
+		err := body.Close()
+		if err != nil {
+
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode >= 500, fmt.Errorf("unexpected status %v", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return nil, true, err
+	}
+
+	return body, false, nil
+}
@@ -0,0 +1,17 @@
+package main
+
+import "strings"
+
+// stringList is a flag.Value that collects every occurrence of a repeatable flag
+type stringList []string
+
+// String implements flag.Value
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set implements flag.Value and appends the value to the list
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+
+	"debateData/debatedata"
+)
+
+// fuzzyMerge is one issue name folded into a more frequent near-duplicate by mergeSimilarIssues
+type fuzzyMerge struct {
+	from     string
+	into     string
+	distance int
+}
+
+// mergeSimilarIssues merges issue names within threshold edits (Levenshtein distance) of a more frequently mentioned
+// issue into that issue, to absorb transcription typos such as "Healtcare". Issues are visited from most to least
+// mentioned, ties broken alphabetically, and each one either starts a cluster or joins the first cluster close
+// enough to it. It returns every merge made so they can be audited.
+func mergeSimilarIssues(debates *[]Debate, threshold int) []fuzzyMerge {
+
+	var totals = make(map[string]int)
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			for issue, count := range candidate.IssueCount {
+				totals[issue] += count
+			}
+		}
+	}
+
+	issues := debatedata.GetIssues(debates)
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return totals[issues[i]] > totals[issues[j]]
+	})
+
+	var heads []string
+	var into = make(map[string]string)
+	var merges []fuzzyMerge
+
+	for _, issue := range issues {
+		var merged = false
+
+		for _, head := range heads {
+			if d := levenshtein(issue, head); d <= threshold {
+				into[issue] = head
+				merges = append(merges, fuzzyMerge{from: issue, into: head, distance: d})
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			heads = append(heads, issue)
+		}
+	}
+
+	if len(merges) == 0 {
+		return nil
+	}
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			for from, head := range into {
+				if count, exists := candidate.IssueCount[from]; exists {
+					candidate.IssueCount[head] += count
+					delete(candidate.IssueCount, from)
+				}
+
+				if weighted, exists := candidate.WeightedCount[from]; exists {
+					candidate.WeightedCount[head] += weighted
+					delete(candidate.WeightedCount, from)
+				}
+			}
+		}
+	}
+
+	return merges
+}
+
+// levenshtein returns the number of single-rune insertions, deletions and substitutions needed to turn a into b
+func levenshtein(a string, b string) int {
+
+	ra, rb := []rune(a), []rune(b)
+
+	// Keep only the previous and current rows of the edit distance table
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// min3 returns the smallest of three ints
+func min3(a int, b int, c int) int {
+	if b < a {
+		a = b
+	}
+
+	if c < a {
+		a = c
+	}
+
+	return a
+}
+
+// summarizeDuplicates lists every pair of issue names fewer than threshold edits apart, with each name's total
+// mentions, so likely typos can be spotted and added to an alias file. Pairs follow the issues' ranking by total
+// mentions, so the first issue of a pair is the more common spelling.
+func summarizeDuplicates(debates *[]Debate, threshold int) [][]string {
+
+	var rows = [][]string{{"Issue", "Count", "SimilarIssue", "SimilarCount", "Distance"}}
+
+	ranked := rankIssues(debates)
+
+	for i, a := range ranked {
+		for _, b := range ranked[i+1:] {
+			if d := levenshtein(a.issue, b.issue); d < threshold {
+				rows = append(rows, []string{a.issue, strconv.Itoa(a.total), b.issue, strconv.Itoa(b.total), strconv.Itoa(d)})
+			}
+		}
+	}
+
+	return rows
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"debateData/debatedata"
+)
+
+// readGroups reads a two-column CSV mapping candidate names to group names. A leading "Candidate,Group" header row is
+// skipped. Candidate names are sanitized the same way as column titles so they match the parsed data.
+func readGroups(fileName string, dialect csvDialect) (map[string]string, error) {
+
+	records, err := readCsv(fileName, dialect)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var groups = make(map[string]string)
+
+	for k, record := range records {
+		if len(record) != 2 {
+			return nil, fmt.Errorf("group file '%v' line %d: expected CANDIDATE,GROUP", fileName, k+1)
+		}
+
+		candidate, group := debatedata.SanitizeColumnName(record[0]), strings.TrimSpace(record[1])
+
+		if k == 0 && strings.EqualFold(candidate, "Candidate") && strings.EqualFold(group, "Group") {
+			continue
+		}
+
+		groups[candidate] = group
+	}
+
+	return groups, nil
+}
+
+// groupCandidates merges the candidates of each debate that belong to the same group into a single candidate named
+// after the group, summing their issue counts. The group takes the position of its first member; candidates without
+// a group pass through unchanged.
+func groupCandidates(debates *[]Debate, groups map[string]string) {
+
+	for k, debate := range *debates {
+
+		var merged []Candidate
+		var groupIndex = make(map[string]int)
+
+		for _, candidate := range debate.Candidates {
+			group, grouped := groups[candidate.Name]
+
+			if !grouped {
+				merged = append(merged, candidate)
+				continue
+			}
+
+			idx, exists := groupIndex[group]
+
+			if !exists {
+				var aggregate = Candidate{Name: group, IssueCount: make(map[string]int)}
+
+				if candidate.WeightedCount != nil {
+					aggregate.WeightedCount = make(map[string]float64)
+				}
+
+				idx = len(merged)
+				groupIndex[group] = idx
+				merged = append(merged, aggregate)
+			}
+
+			for issue, count := range candidate.IssueCount {
+				merged[idx].IssueCount[issue] += count
+			}
+
+			for issue, count := range candidate.WeightedCount {
+				merged[idx].WeightedCount[issue] += count
+			}
+
+			merged[idx].CellIssues = append(merged[idx].CellIssues, candidate.CellIssues...)
+		}
+
+		(*debates)[k].Candidates = merged
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"debateData/debatedata"
+)
+
+// highlight is one -highlight ISSUE:N query: candidates whose count for Issue is at least Threshold
+type highlight struct {
+	Issue     string
+	Threshold float64
+}
+
+// parseHighlight parses a -highlight value of the form ISSUE:N. The issue name may itself contain colons, so the
+// threshold is taken from the last one.
+func parseHighlight(val string) (highlight, error) {
+
+	sep := strings.LastIndex(val, ":")
+
+	if sep <= 0 {
+		return highlight{}, fmt.Errorf("invalid highlight '%v': expected ISSUE:N", val)
+	}
+
+	threshold, err := strconv.ParseFloat(strings.TrimSpace(val[sep+1:]), 64)
+
+	if err != nil {
+		return highlight{}, fmt.Errorf("invalid threshold in highlight '%v': %v", val, err)
+	}
+
+	return highlight{Issue: strings.TrimSpace(val[:sep]), Threshold: threshold}, nil
+}
+
+// summarizeHighlights builds a report with one section per highlight, each listing the debates and candidates whose
+// count for the issue meets the threshold, highest count first. Sections follow the order the highlights were given
+// and are identified by their Issue and Threshold columns. It also returns the highlights that matched nothing.
+func summarizeHighlights(debates *[]Debate, highlights []highlight) ([][]string, []highlight) {
+
+	var rows = [][]string{{"Issue", "Threshold", "Date", "Candidate", "Count"}}
+	var empty []highlight
+
+	for _, h := range highlights {
+
+		type match struct {
+			issue     string
+			date      string
+			candidate string
+			count     float64
+		}
+
+		var matches []match
+
+		for _, debate := range *debates {
+			for _, candidate := range debate.Candidates {
+				for issue := range candidate.IssueCount {
+					if !strings.EqualFold(issue, h.Issue) {
+						continue
+					}
+
+					if count := candidate.Score(issue); count >= h.Threshold {
+						matches = append(matches, match{issue: issue, date: debate.Date, candidate: candidate.Name, count: count})
+					}
+				}
+			}
+		}
+
+		if len(matches) == 0 {
+			empty = append(empty, h)
+			continue
+		}
+
+		// Stable so equal counts keep debate and candidate order
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].count > matches[j].count
+		})
+
+		for _, m := range matches {
+			rows = append(rows, []string{m.issue, debatedata.FormatWeighted(h.Threshold), m.date, m.candidate, debatedata.FormatWeighted(m.count)})
+		}
+	}
+
+	return rows, empty
+}
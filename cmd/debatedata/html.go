@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"os"
+)
+
+// htmlPageHead opens the standalone page written by -html-sortable, with just enough styling to read the table
+const htmlPageHead = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Debate issues</title>
+<style>
+table { border-collapse: collapse; font-family: sans-serif; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; }
+th { background: #f3f3f3; cursor: pointer; }
+td.num { text-align: right; }
+tfoot td { font-weight: bold; }
+</style>
+</head>
+<body>
+`
+
+// htmlSortScript sorts the table body on the clicked column, numerically when both cells are numbers, and reverses
+// the order on a second click. Footer rows such as Total stay at the bottom.
+const htmlSortScript = `<script>
+document.querySelectorAll("table.sortable th").forEach(function (th, col) {
+  th.addEventListener("click", function () {
+    var tbody = th.closest("table").tBodies[0];
+    var rows = Array.from(tbody.rows);
+    var dir = th.dataset.dir === "asc" ? -1 : 1;
+    th.closest("tr").querySelectorAll("th").forEach(function (h) { delete h.dataset.dir; });
+    th.dataset.dir = dir === 1 ? "asc" : "desc";
+    rows.sort(function (a, b) {
+      var x = a.cells[col] ? a.cells[col].textContent : "", y = b.cells[col] ? b.cells[col].textContent : "";
+      var nx = parseFloat(x), ny = parseFloat(y);
+      if (!isNaN(nx) && !isNaN(ny)) return (nx - ny) * dir;
+      return x.localeCompare(y) * dir;
+    });
+    rows.forEach(function (row) { tbody.appendChild(row); });
+  });
+});
+</script>
+`
+
+// writeHtml writes a summary matrix as an HTML table: the first row in a thead, the last footers rows in a tfoot, and
+// the rest in the tbody. Cells of columns that are all numbers (or blank) are right-aligned. Without sortable the
+// output is a bare table fragment for pasting into a larger page; with it, a standalone page whose columns sort when
+// their header is clicked. A non-empty legend of label and issue pairs follows as a second table.
+func writeHtml(fileName string, data [][]string, footers int, sortable bool, legend [][]string) error {
+	var f = os.Stdout
+
+	if fileName != stdio {
+		var err error
+
+		if f, err = os.Create(fileName); err != nil {
+			return fmt.Errorf("could not open html file: %v", err)
+		}
+
+		defer func(f *os.File) {
+			err := f.Close()
+			if err != nil {
+
+			}
+		}(f)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	if footers > len(data)-1 {
+		footers = len(data) - 1
+	}
+
+	w := bufio.NewWriter(f)
+
+	var numeric = make([]bool, len(data[0]))
+
+	for colNum := range numeric {
+		numeric[colNum] = numericColumn(data[1:], colNum)
+	}
+
+	if sortable {
+		w.WriteString(htmlPageHead)
+		w.WriteString("<table class=\"sortable\">\n")
+	} else {
+		w.WriteString("<table>\n")
+	}
+
+	w.WriteString("<thead>\n")
+	writeHtmlRow(w, data[0], "th", nil)
+	w.WriteString("</thead>\n<tbody>\n")
+
+	for _, row := range data[1 : len(data)-footers] {
+		writeHtmlRow(w, row, "td", numeric)
+	}
+
+	w.WriteString("</tbody>\n")
+
+	if footers > 0 {
+		w.WriteString("<tfoot>\n")
+
+		for _, row := range data[len(data)-footers:] {
+			writeHtmlRow(w, row, "td", numeric)
+		}
+
+		w.WriteString("</tfoot>\n")
+	}
+
+	w.WriteString("</table>\n")
+
+	if len(legend) > 0 {
+		w.WriteString("<table class=\"legend\">\n<thead>\n")
+		writeHtmlRow(w, []string{"Label", "Issue"}, "th", nil)
+		w.WriteString("</thead>\n<tbody>\n")
+
+		for _, row := range legend {
+			writeHtmlRow(w, row, "td", nil)
+		}
+
+		w.WriteString("</tbody>\n</table>\n")
+	}
+
+	if sortable {
+		w.WriteString(htmlSortScript)
+		w.WriteString("</body>\n</html>\n")
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("could not write to html file '%v': %v", fileName, err)
+	}
+
+	return nil
+}
+
+// writeHtmlRow writes one table row of tag cells, escaping their text. Cells in numeric columns get the num class.
+func writeHtmlRow(w *bufio.Writer, row []string, tag string, numeric []bool) {
+	w.WriteString("<tr>")
+
+	for colNum, cell := range row {
+		if colNum < len(numeric) && numeric[colNum] {
+			w.WriteString("<" + tag + " class=\"num\">" + html.EscapeString(cell) + "</" + tag + ">")
+		} else {
+			w.WriteString("<" + tag + ">" + html.EscapeString(cell) + "</" + tag + ">")
+		}
+	}
+
+	w.WriteString("</tr>\n")
+}
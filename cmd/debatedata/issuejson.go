@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+)
+
+// issueEntry is one candidate's count for an issue in one debate, in -format issuejson output
+type issueEntry struct {
+	Date      string  `json:"date"`
+	Candidate string  `json:"candidate"`
+	Count     float64 `json:"count"`
+}
+
+// groupByIssue inverts the debate-keyed data into a map from issue to every debate and candidate that raised it, in
+// debate and candidate order. Candidates who never raised an issue have no entry for it.
+func groupByIssue(debates *[]Debate) map[string][]issueEntry {
+
+	var issues = make(map[string][]issueEntry)
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			for issue := range candidate.IssueCount {
+				if count := candidate.Score(issue); count != 0 {
+					issues[issue] = append(issues[issue], issueEntry{Date: debate.Date, Candidate: candidate.Name, Count: count})
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+// writeIssueJson writes the issue-keyed view of the debates as JSON, with issues in alphabetical order
+func writeIssueJson(fileName string, debates *[]Debate, compact bool) error {
+
+	data, err := marshalJson(groupByIssue(debates), compact)
+
+	if err != nil {
+		return fmt.Errorf("could not encode issue json: %v", err)
+	}
+
+	if err = writeOutputFile(fileName, append(data, '\n')); err != nil {
+		return fmt.Errorf("could not write to json file '%v': %v", fileName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"debateData/debatedata"
+)
+
+// readIssueWeights reads a JSON file mapping issue names to score multipliers, e.g. {"Economy": 2, "Trivia": 0.5},
+// and returns a lookup keyed the same way as the alias lookup, so names match regardless of case and surrounding
+// whitespace. Negative weights are an error.
+func readIssueWeights(fileName string) (map[string]float64, error) {
+
+	data, err := os.ReadFile(fileName)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read weights file: %v", err)
+	}
+
+	var listed map[string]float64
+
+	if err = json.Unmarshal(data, &listed); err != nil {
+		return nil, fmt.Errorf("could not decode weights file '%v': %v", fileName, err)
+	}
+
+	var weights = make(map[string]float64)
+
+	for issue, weight := range listed {
+		if weight < 0 {
+			return nil, fmt.Errorf("weights file '%v': '%v' has negative weight %v", fileName, issue, weight)
+		}
+
+		weights[debatedata.AliasKey(issue)] = weight
+	}
+
+	return weights, nil
+}
+
+// issueWeight returns the multiplier for an issue: its listed weight, or 1 when the issue isn't listed
+func issueWeight(weights map[string]float64, issue string) float64 {
+	if weight, exists := weights[debatedata.AliasKey(issue)]; exists {
+		return weight
+	}
+
+	return 1
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"debateData/debatedata"
+)
+
+// marshalJson encodes v as indented JSON, or on a single line when compact is set
+func marshalJson(v interface{}, compact bool) ([]byte, error) {
+	if compact {
+		return json.Marshal(v)
+	}
+
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// writeOutputFile writes data to the named file, or to stdout when the name is "-"
+func writeOutputFile(fileName string, data []byte) error {
+	if fileName == stdio {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	return os.WriteFile(fileName, data, 0644)
+}
+
+// writeJson writes the parsed debates as JSON, keeping the per-debate grouping: each debate object holds its date and
+// the candidates with their issue counts
+func writeJson(fileName string, debates *[]Debate, compact bool) error {
+
+	data, err := marshalJson(debates, compact)
+
+	if err != nil {
+		return fmt.Errorf("could not encode json: %v", err)
+	}
+
+	if err = writeOutputFile(fileName, append(data, '\n')); err != nil {
+		return fmt.Errorf("could not write to json file '%v': %v", fileName, err)
+	}
+
+	return nil
+}
+
+// summaryRow is one candidate row of a summary in -format summaryjson output
+type summaryRow struct {
+	Date      string             `json:"date"`
+	Group     string             `json:"group,omitempty"`
+	Candidate string             `json:"candidate"`
+	Counts    map[string]float64 `json:"counts"`
+}
+
+// summaryDocument is the -format summaryjson output: the summary's candidate rows followed by its Total row
+type summaryDocument struct {
+	Rows   []summaryRow       `json:"rows"`
+	Totals map[string]float64 `json:"totals"`
+}
+
+// summaryJson converts a summary matrix, as built by debatedata.Summarize, into its JSON document. Field order is
+// fixed by the struct definitions and issue keys are sorted by the encoder, so equal summaries encode identically.
+func summaryJson(summary [][]string) (summaryDocument, error) {
+
+	header := summary[0]
+	first := debatedata.FirstIssueColumn(header)
+
+	counts := func(rowNum int) (map[string]float64, error) {
+		var values = make(map[string]float64)
+
+		for colNum := first; colNum < len(header); colNum++ {
+			val, err := strconv.ParseFloat(summary[rowNum][colNum], 64)
+
+			if err != nil {
+				return nil, &DataError{
+					Row:     rowNum,
+					Column:  colNum,
+					Kind:    InvalidCount,
+					Message: fmt.Sprintf("'%v' is not a valid issue count", summary[rowNum][colNum]),
+				}
+			}
+
+			values[header[colNum]] = val
+		}
+
+		return values, nil
+	}
+
+	var doc = summaryDocument{Rows: []summaryRow{}}
+	totalRow := len(summary) - 1
+
+	for rowNum := 1; rowNum < totalRow; rowNum++ {
+		values, err := counts(rowNum)
+
+		if err != nil {
+			return summaryDocument{}, err
+		}
+
+		row := summaryRow{Date: summary[rowNum][0], Candidate: summary[rowNum][first-1], Counts: values}
+
+		if first > 2 {
+			row.Group = summary[rowNum][1]
+		}
+
+		doc.Rows = append(doc.Rows, row)
+	}
+
+	totals, err := counts(totalRow)
+
+	if err != nil {
+		return summaryDocument{}, err
+	}
+
+	doc.Totals = totals
+
+	return doc, nil
+}
+
+// writeSummaryJson writes a summary matrix as a JSON document of candidate rows and a totals block
+func writeSummaryJson(fileName string, summary [][]string, compact bool) error {
+
+	doc, err := summaryJson(summary)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := marshalJson(doc, compact)
+
+	if err != nil {
+		return fmt.Errorf("could not encode summary json: %v", err)
+	}
+
+	if err = writeOutputFile(fileName, append(data, '\n')); err != nil {
+		return fmt.Errorf("could not write to json file '%v': %v", fileName, err)
+	}
+
+	return nil
+}
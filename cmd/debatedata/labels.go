@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// shortenIssueLabels truncates the issue column headers of a summary, from column first on, to at most width runes
+// with a trailing ellipsis. Labels that would collide after truncation get a numeric suffix. It returns the legend
+// rows mapping each shortened label back to its full issue name; the legend is empty when nothing was shortened.
+func shortenIssueLabels(summary [][]string, first int, width int) [][]string {
+
+	var legend [][]string
+	var used = make(map[string]interface{})
+
+	for _, h := range summary[0][:first] {
+		used[h] = nil
+	}
+
+	for colNum := first; colNum < len(summary[0]); colNum++ {
+		name := summary[0][colNum]
+
+		if utf8.RuneCountInString(name) <= width {
+			used[name] = nil
+			continue
+		}
+
+		label := truncateLabel(name, width, "")
+
+		for n := 2; ; n++ {
+			if _, exists := used[label]; !exists {
+				break
+			}
+			label = truncateLabel(name, width, strconv.Itoa(n))
+		}
+
+		used[label] = nil
+		summary[0][colNum] = label
+		legend = append(legend, []string{label, name})
+	}
+
+	return legend
+}
+
+// truncateLabel shortens name so that it, an ellipsis and suffix fit in width runes. At least one rune of the name is
+// always kept.
+func truncateLabel(name string, width int, suffix string) string {
+
+	keep := width - 1 - utf8.RuneCountInString(suffix)
+
+	if keep < 1 {
+		keep = 1
+	}
+
+	return string([]rune(name)[:keep]) + "…" + suffix
+}
+
+// columnLabels are the displayed names of the key columns and totals, which default to the names the summary is
+// built and matched with
+type columnLabels struct {
+	date      string
+	candidate string
+	total     string
+}
+
+// relabelColumns replaces the Date and Candidate headers, a trailing Total column header and the Total row label
+// with their display labels. It runs just before writing, after every step that finds columns by their names; issue
+// columns that happen to be named "Date" or "Total" are left alone.
+func relabelColumns(summary [][]string, labels columnLabels) {
+
+	header := summary[0]
+	candidateColumn := -1
+
+	for k, h := range header {
+		if h == "Candidate" {
+			candidateColumn = k
+			break
+		}
+	}
+
+	for k := 0; k <= candidateColumn; k++ {
+		switch header[k] {
+		case "Date":
+			header[k] = labels.date
+		case "Candidate":
+			header[k] = labels.candidate
+		}
+	}
+
+	if last := len(header) - 1; last > candidateColumn && header[last] == "Total" {
+		header[last] = labels.total
+	}
+
+	if candidateColumn < 0 {
+		return
+	}
+
+	for _, row := range summary[1:] {
+		if candidateColumn < len(row) && row[candidateColumn] == "Total" {
+			row[candidateColumn] = labels.total
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// countPercentCell matches the cells written by -format count+percent, e.g. "3 (15.0%)"
+var countPercentCell = regexp.MustCompile(`^(\S+) \((\S+)%\)$`)
+
+// localizeNumbers rewrites the numeric cells of a summary using the number conventions of a locale, e.g. "1.234,5"
+// for German. Cells with decimals always use the locale's separators; integer cells are only changed when grouping is
+// set, so default count output stays plain. The header row and the columns before firstValueColumn (dates, names) are
+// left alone.
+func localizeNumbers(rows [][]string, tag language.Tag, firstValueColumn int, grouping bool) {
+
+	printer := message.NewPrinter(tag)
+
+	for _, row := range rows[1:] {
+		for colNum := firstValueColumn; colNum < len(row); colNum++ {
+			if match := countPercentCell.FindStringSubmatch(row[colNum]); match != nil {
+				row[colNum] = fmt.Sprintf("%v (%v%%)", localizeNumber(printer, match[1], grouping), localizeNumber(printer, match[2], grouping))
+				continue
+			}
+
+			row[colNum] = localizeNumber(printer, row[colNum], grouping)
+		}
+	}
+}
+
+// localizeNumber formats a single number with the printer, keeping its number of decimal places and any explicit
+// plus sign. Values that aren't numbers are returned unchanged.
+func localizeNumber(printer *message.Printer, val string, grouping bool) string {
+
+	number, err := strconv.ParseFloat(val, 64)
+
+	if err != nil {
+		return val
+	}
+
+	var decimals = 0
+
+	if dot := strings.IndexByte(val, '.'); dot >= 0 {
+		decimals = len(val) - dot - 1
+	}
+
+	if decimals == 0 && !grouping {
+		return val
+	}
+
+	formatted := printer.Sprintf(fmt.Sprintf("%%.%df", decimals), number)
+
+	if strings.HasPrefix(val, "+") {
+		formatted = "+" + formatted
+	}
+
+	return formatted
+}
@@ -0,0 +1,2083 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/language"
+	"golang.org/x/text/transform"
+
+	"debateData/debatedata"
+)
+
+// config holds the resolved command-line options for a run
+type config struct {
+	format    string
+	rankZeros string
+	parse     debatedata.ParseOptions
+	sortMode  string
+	summary   debatedata.SummaryOptions
+
+	verbose bool
+	// compactJson writes the JSON formats on a single line instead of indented
+	compactJson bool
+	checksum    string
+
+	noClobber bool
+	mkdir     bool
+	// outputEncoding is the charset of the CSV output; nil writes UTF-8
+	outputEncoding encoding.Encoding
+	// transliterate substitutes characters the output charset can't represent instead of failing
+	transliterate bool
+	// fuzzyMerge merges issue names within this many edits of a more common issue. 0 disables it.
+	fuzzyMerge int
+	// roster lists the candidates the input is expected to have; nil skips the check. strictRoster turns a mismatch
+	// into an error instead of a warning.
+	roster       []string
+	strictRoster bool
+	// summaryTop prints a digest of the summaryTop most- and least-discussed issues to stderr. 0 disables it.
+	summaryTop int
+	// selfCheck recounts the raw issue tokens after parsing and fails if the parsed counts disagree
+	selfCheck bool
+	// from and to limit the run to debates dated within the inclusive range; a zero time leaves that end open
+	from time.Time
+	to   time.Time
+	// incremental limits the run to debates dated after since, the last date recorded in the -state file
+	incremental bool
+	since       time.Time
+	// normalizeDates rewrites debate dates as YYYY-MM-DD; strictDates makes a date that doesn't parse an error
+	// instead of a warning
+	normalizeDates bool
+	strictDates    bool
+	// keepEmptyDebates keeps debates in which no candidate mentioned any issue, as all-zero rows
+	keepEmptyDebates bool
+	interactive      bool
+
+	highlights []highlight
+	compare    [2]string
+	// report replaces the candidate matrix with one of the report modes, such as reportIssues. Empty disables it.
+	report string
+	// dupeThreshold is the edit distance below which -detect-dupes reports two issue names as likely duplicates
+	dupeThreshold int
+	// issueWeights multiplies each issue's counts in -report weighted, keyed by debatedata.AliasKey; unlisted issues weigh 1
+	issueWeights map[string]float64
+	// topN reports each candidate's topN most-discussed issues instead of the matrix. 0 disables it.
+	topN int
+	// maxIssueWidth truncates issue headers in human-readable output to this many runes, adding a legend. 0 disables it.
+	maxIssueWidth int
+	// candidatesFooter adds a row counting the distinct candidates who discussed each issue
+	candidatesFooter bool
+	// transpose pivots the summary so issues are rows and each candidate row becomes a column
+	transpose bool
+	// labels are the displayed names of the Date and Candidate columns and the totals
+	labels columnLabels
+	// aggregate sums each candidate's counts across every debate into a single row without a Date column
+	aggregate bool
+	// htmlSortable wraps -format html output in a standalone page with a click-to-sort table
+	htmlSortable bool
+	// debateOrder is debateOrderInput or debateOrderDate
+	debateOrder string
+	// dedupeDates keeps only the first debate on each date (and group), for merged inputs that overlap
+	dedupeDates bool
+	// xlsxDebateSheets adds a sheet per debate to -format xlsx output
+	xlsxDebateSheets bool
+
+	dialect       csvDialect
+	headerComment bool
+
+	locale         string
+	localeGrouping bool
+
+	limitRows int
+
+	diffFile      string
+	diffThreshold float64
+
+	sumOnly    bool
+	sumPercent bool
+
+	hashCandidates bool
+	salt           string
+
+	groups map[string]string
+
+	redactIssues stringList
+	redactFold   bool
+	// candidates restricts the output to these candidates when set
+	candidates stringList
+}
+
+// commentChar returns the character that starts comment lines: the input comment character when one is set, so
+// output can be read back with the same -comment-char, otherwise '#'
+func (cfg *config) commentChar() rune {
+	if cfg.dialect.comment != 0 {
+		return cfg.dialect.comment
+	}
+
+	return '#'
+}
+
+// tableFormat reports whether the output format renders the count matrix as a plain table: csv, markdown, html or
+// xlsx
+func (cfg *config) tableFormat() bool {
+	return cfg.format == "csv" || cfg.format == "markdown" || cfg.format == "html" || cfg.format == "xlsx"
+}
+
+// To execute this code, type `go run ./cmd/debatedata` in a terminal
+func main() {
+
+	var cfg config
+
+	flag.StringVar(&cfg.format, "format", "csv", "output format: csv (issue counts), markdown (the csv matrix as a GitHub-flavored table), html (the csv matrix as an HTML table), count+percent (human-readable counts with shares), diversity (issue entropy), ranks (per-debate issue ranks), json (debates with their candidates), ndjson (one JSON object per line), issuejson (JSON keyed by issue), summaryjson (the csv rows and totals as JSON), xlsx (the csv matrix as an Excel workbook) or parquet (long-shape Parquet)")
+	flag.BoolVar(&cfg.htmlSortable, "html-sortable", false, "with -format html, write a standalone page whose table sorts when a column header is clicked")
+	flag.BoolVar(&cfg.xlsxDebateSheets, "xlsx-debate-sheets", false, "with -format xlsx, add a sheet per debate after the Summary sheet")
+	flag.StringVar(&cfg.rankZeros, "rank-zeros", "blank", "how -format ranks renders a zero count: blank or lowest")
+	flag.StringVar(&cfg.parse.RoundAgg, "round-agg", debatedata.RoundAggSum, "how a candidate's round columns combine: sum, max or distinct")
+	flag.StringVar(&cfg.parse.CountMode, "count", debatedata.CountMentions, "how repeated issues within one cell count: mentions (every repetition) or rounds (at most once per cell)")
+	dateColIndex := flag.Int("date-col-index", -1, "zero-based position of the date column, for headers that don't label it")
+	flag.BoolVar(&cfg.parse.FoldAccents, "fold-accents", false, "merge candidate and issue names that differ only in accents (e.g. José and Jose)")
+	aliasFile := flag.String("aliases", "", "JSON or YAML (.yaml, .yml) file mapping canonical issue names to lists of variants to merge into them")
+	candidateAliasFile := flag.String("candidate-aliases", "", "JSON or YAML (.yaml, .yml) file mapping canonical candidate names to lists of header variants whose columns are merged into them")
+	flag.BoolVar(&cfg.parse.IgnoreIssueCase, "ignore-issue-case", false, "merge issue names that differ only in case (e.g. economy and Economy)")
+	flag.StringVar(&cfg.parse.CaseCanonical, "case-canonical", debatedata.CaseCanonicalFirst, "display form for issues merged by -ignore-issue-case: first or most-common")
+	dateColumn := flag.String("date-column", "", "exact header name of the date column (default: any header containing \"Date\")")
+	groupColumn := flag.String("group-column", "", "name of a metadata column (e.g. Region) carried into the output between Date and Candidate")
+	tz := flag.String("tz", "UTC", "IANA time zone (e.g. America/New_York) used to parse and bucket debate dates")
+	disallowIssues := flag.String("disallow-issues", defaultDisallowIssues, "regular expression for issue tokens that are dropped with a warning (empty allows all)")
+	flag.BoolVar(&cfg.parse.StrictIssues, "strict-issues", false, "fail on issue tokens matching -disallow-issues instead of dropping them")
+	roundWeights := flag.String("round-weights", "", "weight each round's counts, e.g. 1:1,2:1.5,3:2 (unlisted rounds weigh 1)")
+	flag.BoolVar(&cfg.summary.FlattenRounds, "flatten-rounds", false, "give each issue a column per debate round, e.g. \"Economy (R1)\" and \"Economy (R2)\", instead of combining the rounds")
+	flag.StringVar(&cfg.sortMode, "sort-candidates", sortByName, "candidate row order within each debate: name, total or none")
+	flag.StringVar(&cfg.sortMode, "sort", sortByName, "shorthand for -sort-candidates")
+	flag.BoolVar(&cfg.hashCandidates, "hash-candidates", false, "replace candidate names with a stable salted hash")
+	flag.StringVar(&cfg.salt, "salt", "", "salt mixed into -hash-candidates hashes")
+	flag.BoolVar(&cfg.sumOnly, "sum-only", false, "output only the issue header and the grand-total row")
+	flag.BoolVar(&cfg.sumPercent, "sum-percent", false, "with -sum-only, show each issue's share of all mentions as a percentage")
+	flag.BoolVar(&cfg.candidatesFooter, "count-candidates-per-issue", false, "add a Candidates footer row with the number of distinct candidates who discussed each issue")
+	flag.IntVar(&cfg.maxIssueWidth, "max-issue-width", 0, "with -format count+percent, markdown or html, shorten issue headers to N characters and list the full names in a legend (0 keeps full names)")
+	flag.BoolVar(&cfg.summary.Percent, "percent", false, "output each cell as a percentage of the candidate's mentions in that debate (Total row: share of all mentions)")
+	flag.BoolVar(&cfg.summary.RowTotals, "row-totals", false, "add a trailing Total column with each candidate's mentions across all issues")
+	flag.BoolVar(&cfg.summary.DebateSubtotals, "debate-subtotals", false, "add a Subtotal row after each debate's candidates with that debate's mentions of each issue")
+	flag.BoolVar(&cfg.summary.Sparse, "sparse", false, "drop issue columns whose grand total is zero")
+	flag.BoolVar(&cfg.transpose, "transpose", false, "pivot the output so issues are rows and each candidate (with the debate date) is a column, with Total last")
+	flag.StringVar(&cfg.labels.date, "label-date", "Date", "header text for the Date column in the output")
+	flag.StringVar(&cfg.labels.candidate, "label-candidate", "Candidate", "header text for the Candidate column in the output")
+	flag.StringVar(&cfg.labels.total, "label-total", "Total", "text for the Total row and column in the output")
+	flag.BoolVar(&cfg.dedupeDates, "dedupe-dates", false, "when merged inputs hold several debates on the same date (and group), keep only the first and warn about the rest")
+	flag.BoolVar(&cfg.aggregate, "aggregate", false, "sum each candidate's issue counts across all debates into one row per candidate, without a Date column")
+	var exclude stringList
+	flag.Var(&exclude, "exclude", "comma-separated issues, matched case-insensitively, to leave out of every output (repeatable)")
+	flag.IntVar(&cfg.summary.MinTotal, "min", 0, "drop issues mentioned fewer than this many times in total across all candidates and debates")
+	flag.BoolVar(&cfg.summary.Cumulative, "cumulative", false, "sort debates by date and show running totals per candidate instead of per-debate counts")
+	groupFile := flag.String("group", "", "CSV mapping candidate names to group names; each group's candidates are summed into one row")
+	flag.Var(&cfg.candidates, "candidate", "only include these candidates, matched case-insensitively (repeatable or comma-separated)")
+	flag.Var(&cfg.redactIssues, "redact-issue", "drop this issue from the output (repeatable)")
+	flag.BoolVar(&cfg.redactFold, "redact-fold", false, "fold redacted issues into a single \"Redacted\" column so totals still count them")
+	flag.BoolVar(&cfg.noClobber, "no-clobber", false, "fail instead of overwriting an existing output file")
+	flag.BoolVar(&cfg.parse.SkipBadRows, "skip-bad-rows", false, "skip data rows whose column count differs from the header's with a warning, instead of failing")
+	flag.BoolVar(&cfg.normalizeDates, "normalize-dates", false, "rewrite debate dates such as 11/5/2020 or Nov 5 2020 as ISO YYYY-MM-DD")
+	var dateLayoutList stringList
+	flag.Var(&dateLayoutList, "date-layout", "additional debate date layout in Go's reference form, e.g. 02.01.2006, tried before the built-in ones (repeatable)")
+	flag.StringVar(&cfg.debateOrder, "sort-debates", debateOrderInput, "debate order in the output: input, or date to sort chronologically (failing on a date that can't be parsed)")
+	flag.BoolVar(&cfg.strictDates, "strict-dates", false, "fail on a debate date that matches no known layout instead of warning and passing it through")
+	flag.BoolVar(&cfg.keepEmptyDebates, "keep-empty-debates", true, "keep debates with no transcribed issues as all-zero placeholder rows (-keep-empty-debates=false drops them)")
+	outputEncoding := flag.String("output-encoding", "", "charset of the CSV output, e.g. ISO-8859-1 (default UTF-8)")
+	inputEncoding := flag.String("input-encoding", "", "charset of the CSV input, e.g. ISO-8859-1 (default UTF-8)")
+	flag.BoolVar(&cfg.transliterate, "transliterate", false, "with -output-encoding, replace characters the charset can't represent instead of failing")
+	colorMode := flag.String("color", colorAuto, "colorize warnings and errors on stderr: auto (when stderr is a terminal and NO_COLOR is unset), always or never")
+	flag.IntVar(&cfg.summaryTop, "summary-top", 0, "print a digest of the N most- and least-discussed issues, with totals and shares, to stderr")
+	flag.IntVar(&cfg.fuzzyMerge, "fuzzy-merge", 0, "merge issue names within N edits of a more frequently mentioned issue, reporting each merge on stderr (0 disables)")
+	rosterFile := flag.String("require-candidates", "", "file listing the expected candidates, one per line; missing and extra candidates are reported")
+	flag.BoolVar(&cfg.strictRoster, "strict", false, "with -require-candidates, fail when the candidates don't match the roster")
+	flag.BoolVar(&cfg.selfCheck, "self-check", false, "verify the parsed issue counts add up to the issue tokens in the raw cells (requires -round-agg sum)")
+	fromDate := flag.String("from", "", "only summarize debates on or after this date (YYYY-MM-DD)")
+	toDate := flag.String("to", "", "only summarize debates on or before this date (YYYY-MM-DD)")
+	stateFile := flag.String("state", "", "JSON file that records the latest debate date processed, updated after each successful run")
+	flag.BoolVar(&cfg.incremental, "incremental", false, "with -state, only summarize debates dated after the one recorded in the state file")
+	flag.BoolVar(&cfg.mkdir, "mkdir", false, "create the output directory tree if it does not exist")
+	flag.BoolVar(&cfg.interactive, "i", false, "ask for confirmation on the terminal before overwriting an existing output file")
+	flag.StringVar(&cfg.checksum, "checksum", "", "write a sha256sum-compatible checksum file next to the output (only sha256 is supported)")
+	validateOnly := flag.Bool("validate", false, "check the input for data problems and that its summary totals reconcile, report every problem found and exit")
+	maxErrors := flag.Int("max-errors", 0, "with -validate, stop listing problems after this many (0 lists all)")
+	listCandidatesOnly := flag.Bool("list-candidates", false, "print the candidate names found in the input and exit")
+	statsOnly := flag.Bool("stats", false, "print the number of debates, candidates, issues and mentions and the date range, without writing any output")
+	flag.BoolVar(&cfg.compactJson, "compact", false, "write -format json, issuejson and summaryjson on a single line instead of indented")
+	flag.BoolVar(&cfg.verbose, "verbose", false, "print extra detail")
+	cpuProfile := flag.String("cpuprofile", "", "write a CPU profile of the run to this file")
+	memProfile := flag.String("memprofile", "", "write a heap profile to this file when the run finishes")
+	var highlightFlags stringList
+	flag.Var(&highlightFlags, "highlight", "report candidates whose count for ISSUE is at least N, as ISSUE:N (repeatable, one section each)")
+	flag.StringVar(&cfg.diffFile, "diff", "", "compare the summary against this previous summary CSV and output the per-cell changes")
+	flag.Float64Var(&cfg.diffThreshold, "diff-threshold", 0, "with -diff, blank out cells whose absolute change is below this")
+	flag.StringVar(&cfg.locale, "locale", "", "format decimal output numbers for this locale, e.g. de for 1.234,5")
+	flag.BoolVar(&cfg.localeGrouping, "locale-grouping", false, "with -locale, also apply thousands grouping to whole-number counts")
+	commentChar := flag.String("comment-char", "", "skip input lines starting with this character")
+	delimiter := flag.String("delimiter", ",", "field delimiter for the input and CSV output, e.g. ; or \\t for tab-separated files")
+	flag.BoolVar(&cfg.headerComment, "header-comment", false, "start the output with a comment line recording when and from what it was generated")
+	flag.IntVar(&cfg.limitRows, "limit-rows", 0, "keep only the first N candidate rows after sorting, plus the header and totals (0 keeps all)")
+	flag.IntVar(&cfg.topN, "topn", 0, "output each candidate's N most-discussed issues with their counts instead of the matrix")
+	flag.StringVar(&cfg.report, "report", "", "output a report instead of the candidate matrix: issues (issues ranked by total mentions) trends (issues by debate date, chronologically), weighted (issue scores using -weights) or cooccurrence (issue pairs raised together in one cell)")
+	detectDupes := flag.Bool("detect-dupes", false, "instead of summarizing, list pairs of issue names fewer than -dupe-threshold edits apart with their counts")
+	flag.IntVar(&cfg.dupeThreshold, "dupe-threshold", 2, "edit distance below which -detect-dupes reports two issue names")
+	weightsFile := flag.String("weights", "", "JSON file mapping issue names to score multipliers for -report weighted, e.g. {\"Economy\": 2}")
+	comparePair := flag.String("compare", "", "output a head-to-head issue table for two candidates, as A,B")
+	manifestFile := flag.String("manifest", "", "write a JSON manifest describing the run to this file")
+	inDir := flag.String("in-dir", "", "summarize every *.csv file in this directory separately (requires -out-dir)")
+	outDir := flag.String("out-dir", "", "directory that receives one summary per -in-dir input, named after the input")
+	continueOnError := flag.Bool("continue-on-error", false, "with -in-dir, keep processing the remaining files when one fails")
+	archiveFile := flag.String("archive", "", "read the job config and input CSV from this zip bundle")
+	archiveEntry := flag.String("archive-entry", "", "with -archive, the CSV entry to summarize (defaults to the config's input)")
+	archiveOut := flag.String("archive-out", "", "with -archive, also write a copy of the bundle with the output added to this zip")
+	inputFlag := flag.String("in", "./debate_data.csv", "input CSV file, - for stdin, or an http(s) URL to fetch it from")
+	retries := flag.Int("retries", 0, "retry a URL input this many times on server errors and network failures")
+	retryDelay := flag.Duration("retry-delay", time.Second, "wait before the first URL retry; doubles for each retry after it")
+	outputFlag := flag.String("out", "./output.csv", "output file, or - for stdout")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %v [summarize] [flags] [input.csv ...]\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %v validate [flags] [input.csv ...]\n\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "Summarizes the issues each candidate discussed per debate, reading -in and writing -out.")
+		fmt.Fprintln(flag.CommandLine.Output(), "Input files named after the flags replace -in and are merged; - reads stdin or writes stdout.")
+		fmt.Fprintln(flag.CommandLine.Output(), "Input names may be glob patterns such as data/*.csv. The validate command is the same as -validate.")
+		fmt.Fprintln(flag.CommandLine.Output(), "\nFlags:")
+		flag.PrintDefaults()
+	}
+
+	// A leading validate command is shorthand for the -validate flag; summarize is what runs by default
+	args := os.Args[1:]
+
+	if len(args) > 0 && args[0] == "validate" {
+		args = append([]string{"-validate"}, args[1:]...)
+	} else if len(args) > 0 && args[0] == "summarize" {
+		args = args[1:]
+	}
+
+	// The command line flag set exits on a bad flag, as flag.Parse does, so there is no error to handle
+	flag.CommandLine.Parse(args)
+
+	// An archive bundles the input with its config. The config fills in any flags not given on the command line, so it
+	// is applied before anything is derived from the flags.
+	var archiveCfg *archiveConfig
+
+	if *archiveFile != "" {
+		var err error
+
+		if archiveCfg, err = readArchiveConfig(*archiveFile); err != nil {
+			printError(err)
+			exit(1)
+		}
+
+		if err = applyArchiveFlags(archiveCfg); err != nil {
+			printError(err)
+			exit(1)
+		}
+	}
+
+	for _, list := range exclude {
+		for _, name := range strings.Split(list, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				cfg.summary.ExcludeIssues = append(cfg.summary.ExcludeIssues, name)
+			}
+		}
+	}
+
+	// Input files given as arguments after the flags replace -in and are merged into one summary
+	inputFiles := []string{*inputFlag}
+
+	if flag.NArg() > 0 {
+		inputFiles = expandGlobs(flag.Args())
+	}
+
+	// Stdin can be read only once, so a second - would leave one of the readers with nothing
+	var stdinInputs = 0
+
+	for _, inputFile := range inputFiles {
+		if inputFile == stdio {
+			stdinInputs++
+		}
+	}
+
+	if stdinInputs > 1 {
+		printError(fmt.Errorf("- (stdin) can be given only once as an input; use -out - to write to stdout"))
+		exit(1)
+	}
+	outputFile := *outputFlag
+
+	if *disallowIssues != "" {
+		pattern, err := regexp.Compile(*disallowIssues)
+
+		if err != nil {
+			printError(fmt.Errorf("invalid -disallow-issues pattern: %v", err))
+			exit(1)
+		}
+
+		cfg.parse.DisallowIssues = pattern
+	}
+
+	if err := setColorMode(*colorMode); err != nil {
+		printError(err)
+		exit(1)
+	}
+
+	cfg.parse.Warn = func(err *DataError) {
+		warnf("%v", err)
+	}
+
+	if *commentChar != "" {
+		if utf8.RuneCountInString(*commentChar) != 1 {
+			printError(fmt.Errorf("invalid -comment-char '%v': expected a single character", *commentChar))
+			exit(1)
+		}
+
+		cfg.dialect.comment, _ = utf8.DecodeRuneInString(*commentChar)
+	}
+
+	if *delimiter == `\t` {
+		*delimiter = "\t"
+	}
+
+	if utf8.RuneCountInString(*delimiter) != 1 || strings.ContainsAny(*delimiter, "\"\r\n") {
+		printError(fmt.Errorf("invalid -delimiter '%v': expected a single character other than a quote or line break", *delimiter))
+		exit(1)
+	}
+
+	cfg.dialect.comma, _ = utf8.DecodeRuneInString(*delimiter)
+
+	if cfg.dialect.comma == cfg.dialect.comment {
+		printError(fmt.Errorf("-delimiter and -comment-char must differ"))
+		exit(1)
+	}
+
+	if *inputEncoding != "" {
+		enc, err := lookupEncoding(*inputEncoding)
+
+		if err != nil {
+			printError(fmt.Errorf("invalid -input-encoding: %v", err))
+			exit(1)
+		}
+
+		cfg.dialect.encoding = enc
+	}
+
+	// The archive's CSV entry is read with the dialect its config may have set
+	var archiveRecords [][]string
+
+	if archiveCfg != nil {
+		records, err := readArchive(*archiveFile, *archiveEntry, archiveCfg, cfg.dialect)
+
+		if err != nil {
+			printError(err)
+			exit(1)
+		}
+
+		if flag.NArg() > 0 {
+			printError(fmt.Errorf("-archive cannot be combined with input file arguments"))
+			exit(1)
+		}
+
+		inputFiles = []string{*archiveFile}
+		archiveRecords = records
+	}
+
+	readInput := func(inputFile string) ([][]string, error) {
+		if archiveRecords != nil {
+			return archiveRecords, nil
+		}
+
+		if isURL(inputFile) {
+			policy := retryPolicy{retries: *retries, delay: *retryDelay}
+
+			if cfg.verbose {
+				policy.log = os.Stderr
+			}
+
+			return fetchCsv(inputFile, cfg.dialect, policy)
+		}
+
+		return readCsv(inputFile, cfg.dialect)
+	}
+
+	readInputs := func() ([][][]string, error) {
+		var datasets [][][]string
+
+		for _, inputFile := range inputFiles {
+			csvFile, err := readInput(inputFile)
+
+			if err != nil {
+				return nil, err
+			}
+
+			datasets = append(datasets, csvFile)
+		}
+
+		return datasets, nil
+	}
+
+	// Local files and stdin are parsed while they are read. Archives and URLs are read whole, and the self-check
+	// recounts the raw records after parsing, so those keep them in memory.
+	canStream := func() bool {
+		if archiveRecords != nil || cfg.selfCheck {
+			return false
+		}
+
+		for _, inputFile := range inputFiles {
+			if isURL(inputFile) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	stopProfiling, err := startProfiling(*cpuProfile, *memProfile)
+
+	if err != nil {
+		printError(err)
+		exit(1)
+	}
+
+	exitHooks = append(exitHooks, stopProfiling)
+	defer stopProfiling()
+
+	switch cfg.parse.RoundAgg {
+	case debatedata.RoundAggSum, debatedata.RoundAggMax, debatedata.RoundAggDistinct:
+	default:
+		printError(fmt.Errorf("invalid -round-agg value '%v': expected sum, max or distinct", cfg.parse.RoundAgg))
+		exit(1)
+	}
+
+	switch cfg.parse.CountMode {
+	case debatedata.CountMentions, debatedata.CountRounds:
+	default:
+		printError(fmt.Errorf("invalid -count value '%v': expected mentions or rounds", cfg.parse.CountMode))
+		exit(1)
+	}
+
+	if cfg.maxIssueWidth < 0 || (cfg.maxIssueWidth > 0 && cfg.format != "count+percent" && cfg.format != "markdown" && cfg.format != "html") {
+		printError(fmt.Errorf("-max-issue-width needs a positive width and -format count+percent, markdown or html"))
+		exit(1)
+	}
+
+	if *outputEncoding != "" {
+		if cfg.outputEncoding, err = lookupEncoding(*outputEncoding); err != nil {
+			printError(fmt.Errorf("invalid -output-encoding: %v", err))
+			exit(1)
+		}
+	}
+
+	if cfg.summary.RowTotals && (cfg.format == "count+percent" || cfg.sumPercent || cfg.summary.Percent) {
+		printError(fmt.Errorf("-row-totals cannot be combined with -format count+percent, -sum-percent or -percent"))
+		exit(1)
+	}
+
+	if cfg.summary.Percent && cfg.format == "count+percent" {
+		printError(fmt.Errorf("-percent cannot be combined with -format count+percent, which already shows shares"))
+		exit(1)
+	}
+
+	if cfg.candidatesFooter && (cfg.sumOnly || cfg.diffFile != "") {
+		printError(fmt.Errorf("-count-candidates-per-issue cannot be combined with -sum-only or -diff"))
+		exit(1)
+	}
+
+	if cfg.sumOnly && cfg.diffFile != "" {
+		printError(fmt.Errorf("-sum-only cannot be combined with -diff, which needs the Date and Candidate columns"))
+		exit(1)
+	}
+
+	if cfg.aggregate && (cfg.summary.Cumulative || cfg.diffFile != "") {
+		printError(fmt.Errorf("-aggregate cannot be combined with -cumulative or -diff, which work per debate"))
+		exit(1)
+	}
+
+	if cfg.aggregate && !cfg.tableFormat() && cfg.format != "count+percent" {
+		printError(fmt.Errorf("-aggregate requires -format csv, markdown, html, xlsx or count+percent"))
+		exit(1)
+	}
+
+	if cfg.htmlSortable && cfg.format != "html" {
+		printError(fmt.Errorf("-html-sortable requires -format html"))
+		exit(1)
+	}
+
+	if cfg.xlsxDebateSheets && cfg.format != "xlsx" {
+		printError(fmt.Errorf("-xlsx-debate-sheets requires -format xlsx"))
+		exit(1)
+	}
+
+	if cfg.summary.DebateSubtotals && (cfg.summary.Cumulative || cfg.aggregate || cfg.sumOnly || cfg.diffFile != "") {
+		printError(fmt.Errorf("-debate-subtotals cannot be combined with -cumulative, -aggregate, -sum-only or -diff"))
+		exit(1)
+	}
+
+	if *dateColIndex >= 0 {
+		cfg.parse.DateColIndex = dateColIndex
+	}
+
+	if *dateColumn != "" {
+		cfg.parse.DateColumn = debatedata.SanitizeColumnName(*dateColumn)
+
+		if cfg.parse.DateColIndex != nil {
+			printError(fmt.Errorf("-date-column cannot be combined with -date-col-index"))
+			exit(1)
+		}
+	}
+
+	if *groupColumn != "" {
+		cfg.parse.GroupColumn = debatedata.SanitizeColumnName(*groupColumn)
+		cfg.summary.GroupColumn = cfg.parse.GroupColumn
+
+		if strings.EqualFold(cfg.parse.GroupColumn, "Candidate") || strings.EqualFold(cfg.parse.GroupColumn, "Date") {
+			printError(fmt.Errorf("invalid -group-column '%v': the name is reserved", *groupColumn))
+			exit(1)
+		}
+
+		if cfg.diffFile != "" {
+			printError(fmt.Errorf("-group-column cannot be combined with -diff"))
+			exit(1)
+		}
+	}
+
+	if outputFile == stdio && cfg.checksum != "" {
+		printError(fmt.Errorf("-checksum needs an output file, not stdout"))
+		exit(1)
+	}
+
+	if cfg.format == "parquet" && cfg.parse.RoundWeights != nil {
+		printError(fmt.Errorf("-format parquet writes integer counts and cannot be combined with -round-weights"))
+		exit(1)
+	}
+
+	if *rosterFile != "" {
+		roster, err := readRoster(*rosterFile, cfg.commentChar())
+
+		if err != nil {
+			printError(err)
+			exit(1)
+		}
+
+		cfg.roster = roster
+	}
+
+	if cfg.selfCheck && (cfg.parse.RoundAgg != debatedata.RoundAggSum || cfg.parse.CountMode != debatedata.CountMentions) {
+		printError(fmt.Errorf("-self-check requires -round-agg sum and -count mentions, where every issue token counts once"))
+		exit(1)
+	}
+
+	switch cfg.parse.CaseCanonical {
+	case debatedata.CaseCanonicalFirst, debatedata.CaseCanonicalMostCommon:
+	default:
+		printError(fmt.Errorf("invalid -case-canonical value '%v': expected first or most-common", cfg.parse.CaseCanonical))
+		exit(1)
+	}
+
+	switch cfg.sortMode {
+	case sortByName, sortByTotal, sortNone:
+	default:
+		printError(fmt.Errorf("invalid -sort value '%v': expected name, total or none", cfg.sortMode))
+		exit(1)
+	}
+
+	switch cfg.debateOrder {
+	case debateOrderInput, debateOrderDate:
+	default:
+		printError(fmt.Errorf("invalid -sort-debates value '%v': expected input or date", cfg.debateOrder))
+		exit(1)
+	}
+
+	if err = addDateLayouts(dateLayoutList); err != nil {
+		printError(fmt.Errorf("invalid -date-layout: %v", err))
+		exit(1)
+	}
+
+	if cfg.checksum != "" && cfg.checksum != "sha256" {
+		printError(fmt.Errorf("unsupported -checksum algorithm '%v': only sha256 is supported", cfg.checksum))
+		exit(1)
+	}
+
+	cfg.parse.Location, err = time.LoadLocation(*tz)
+
+	if err != nil {
+		printError(fmt.Errorf("invalid -tz value '%v': %v", *tz, err))
+		exit(1)
+	}
+
+	if *fromDate != "" {
+		if cfg.from, err = time.ParseInLocation("2006-01-02", *fromDate, cfg.parse.Location); err != nil {
+			printError(fmt.Errorf("invalid -from date '%v': expected YYYY-MM-DD", *fromDate))
+			exit(1)
+		}
+	}
+
+	if *toDate != "" {
+		if cfg.to, err = time.ParseInLocation("2006-01-02", *toDate, cfg.parse.Location); err != nil {
+			printError(fmt.Errorf("invalid -to date '%v': expected YYYY-MM-DD", *toDate))
+			exit(1)
+		}
+	}
+
+	if *aliasFile != "" {
+		if cfg.parse.Aliases, err = readAliases(*aliasFile); err != nil {
+			printError(err)
+			exit(1)
+		}
+	}
+
+	if *candidateAliasFile != "" {
+		if cfg.parse.CandidateAliases, err = readAliases(*candidateAliasFile); err != nil {
+			printError(err)
+			exit(1)
+		}
+
+		cfg.parse.AliasApplied = aliasReporter()
+	}
+
+	if *groupFile != "" {
+		if cfg.groups, err = readGroups(*groupFile, cfg.dialect); err != nil {
+			printError(err)
+			exit(1)
+		}
+	}
+
+	for _, val := range highlightFlags {
+		h, err := parseHighlight(val)
+
+		if err != nil {
+			printError(err)
+			exit(1)
+		}
+
+		cfg.highlights = append(cfg.highlights, h)
+	}
+
+	if *comparePair != "" {
+		if cfg.compare[0], cfg.compare[1], err = parseComparePair(*comparePair); err != nil {
+			printError(err)
+			exit(1)
+		}
+	}
+
+	if *detectDupes {
+		if cfg.report != "" {
+			printError(fmt.Errorf("-detect-dupes cannot be combined with -report"))
+			exit(1)
+		}
+
+		cfg.report = reportDupes
+	}
+
+	switch cfg.report {
+	case "":
+	case reportIssues, reportDupes, reportTrends, reportWeighted, reportCooccurrence:
+		if !cfg.tableFormat() || len(cfg.highlights) > 0 || cfg.topN > 0 || cfg.compare[0] != "" || cfg.aggregate || cfg.sumOnly || cfg.diffFile != "" {
+			printError(fmt.Errorf("-report requires -format csv, markdown, html or xlsx and cannot be combined with other alternative outputs"))
+			exit(1)
+		}
+	default:
+		printError(fmt.Errorf("invalid -report value '%v': expected issues, trends, weighted or cooccurrence", cfg.report))
+		exit(1)
+	}
+
+	// Pairs are taken from the parsed cells, which later merges and redactions of issue names don't rewrite
+	if cfg.report == reportCooccurrence {
+		if cfg.fuzzyMerge > 0 || len(cfg.redactIssues) > 0 {
+			printError(fmt.Errorf("-report cooccurrence cannot be combined with -fuzzy-merge or -redact-issue"))
+			exit(1)
+		}
+
+		cfg.parse.KeepCellIssues = true
+	}
+
+	if (*weightsFile != "") != (cfg.report == reportWeighted) {
+		printError(fmt.Errorf("-weights and -report weighted must be used together"))
+		exit(1)
+	}
+
+	if *weightsFile != "" {
+		if cfg.issueWeights, err = readIssueWeights(*weightsFile); err != nil {
+			printError(err)
+			exit(1)
+		}
+	}
+
+	if cfg.transpose && (!cfg.tableFormat() && cfg.format != "count+percent" || len(cfg.highlights) > 0 || cfg.topN > 0 || cfg.compare[0] != "" || cfg.report != "" || cfg.sumOnly || cfg.maxIssueWidth > 0) {
+		printError(fmt.Errorf("-transpose only applies to the count matrix of -format csv, markdown, html, xlsx or count+percent"))
+		exit(1)
+	}
+
+	// Flattened rounds only exist in the count matrix; steps that rework the combined counts can't see them
+	if cfg.summary.FlattenRounds {
+		if !cfg.tableFormat() && cfg.format != "count+percent" && cfg.format != "summaryjson" || len(cfg.highlights) > 0 || cfg.topN > 0 || cfg.compare[0] != "" || cfg.report != "" || cfg.sumOnly || cfg.aggregate || cfg.candidatesFooter {
+			printError(fmt.Errorf("-flatten-rounds only applies to the count matrix of -format csv, markdown, html, xlsx, count+percent or summaryjson"))
+			exit(1)
+		}
+
+		if *roundWeights != "" || *groupFile != "" || len(cfg.redactIssues) > 0 || cfg.fuzzyMerge > 0 {
+			printError(fmt.Errorf("-flatten-rounds cannot be combined with -round-weights, -group, -redact-issue or -fuzzy-merge"))
+			exit(1)
+		}
+
+		cfg.parse.KeepRounds = true
+	}
+
+	if *roundWeights != "" {
+		weights, err := parseRoundWeights(*roundWeights)
+
+		if err != nil {
+			printError(err)
+			exit(1)
+		}
+
+		cfg.parse.RoundWeights = weights
+		cfg.summary.Weighted = true
+	}
+
+	if cfg.rankZeros != "blank" && cfg.rankZeros != "lowest" {
+		printError(fmt.Errorf("invalid -rank-zeros value '%v': expected blank or lowest", cfg.rankZeros))
+		exit(1)
+	}
+
+	// The state file stays locked for the whole run so concurrent runs can't interleave their reads and updates
+	var state runState
+
+	if *stateFile != "" {
+		releaseState, err := lockState(*stateFile)
+
+		if err != nil {
+			printError(err)
+			exit(1)
+		}
+
+		exitHooks = append(exitHooks, releaseState)
+		defer releaseState()
+
+		if state, err = readState(*stateFile); err != nil {
+			printError(err)
+			exit(1)
+		}
+
+		cfg.since = state.LastDate
+	} else if cfg.incremental {
+		printError(fmt.Errorf("-incremental requires -state"))
+		exit(1)
+	}
+
+	if *validateOnly {
+		var failed = false
+
+		for _, inputFile := range inputFiles {
+			csvFile, err := readInput(inputFile)
+
+			if err != nil {
+				printError(err)
+				exit(1)
+			}
+
+			collector := errorCollector{max: *maxErrors}
+
+			if err = validateCsvData(csvFile, cfg.parse, &collector); err != nil {
+				printError(err)
+				exit(1)
+			}
+
+			printValidation(os.Stdout, inputFile, &collector)
+
+			if collector.failed() {
+				failed = true
+			}
+		}
+
+		if failed {
+			exit(1)
+		}
+		return
+	}
+
+	if *listCandidatesOnly {
+		datasets, err := readInputs()
+
+		if err != nil {
+			printError(err)
+			exit(1)
+		}
+
+		debates, err := mergeDebates(cfg.parse, datasets...)
+
+		if err != nil {
+			exitOnDataError(err)
+			printError(err)
+			exit(1)
+		}
+
+		listCandidates(os.Stdout, &debates, cfg.verbose)
+		return
+	}
+
+	if *statsOnly {
+		datasets, err := readInputs()
+
+		if err != nil {
+			printError(err)
+			exit(1)
+		}
+
+		debates, err := mergeDebates(cfg.parse, datasets...)
+
+		if err != nil {
+			exitOnDataError(err)
+			printError(err)
+			exit(1)
+		}
+
+		printStats(os.Stdout, collectStats(&debates, cfg.parse.Location))
+		return
+	}
+
+	var inputs []string
+	var debates []Debate
+
+	if *inDir != "" {
+		if *outDir == "" {
+			printError(fmt.Errorf("-in-dir requires -out-dir"))
+			exit(1)
+		}
+
+		if flag.NArg() > 0 {
+			printError(fmt.Errorf("-in-dir cannot be combined with input file arguments"))
+			exit(1)
+		}
+
+		var err error
+		outputFile = *outDir
+		inputs, debates, err = processDir(&cfg, *inDir, *outDir, *continueOnError)
+
+		if err != nil {
+			printError(err)
+			exit(1)
+		}
+	} else {
+		inputs = inputFiles
+
+		var err error
+
+		if canStream() {
+			var parsed []Debate
+
+			if parsed, err = streamDebates(cfg.parse, cfg.dialect, inputFiles); err != nil {
+				exitOnDataError(err)
+				printError(err)
+				exit(1)
+			}
+
+			debates, err = processDebates(&cfg, strings.Join(inputFiles, ", "), outputFile, parsed)
+		} else {
+			var datasets [][][]string
+
+			if datasets, err = readInputs(); err != nil {
+				printError(err)
+				exit(1)
+			}
+
+			debates, err = processRecords(&cfg, strings.Join(inputFiles, ", "), outputFile, datasets...)
+		}
+
+		if err != nil {
+			exitOnDataError(err)
+			printError(err)
+			exit(1)
+		}
+
+		if *archiveOut != "" {
+			if err = writeArchive(*archiveFile, *archiveOut, outputFile); err != nil {
+				printError(err)
+				exit(1)
+			}
+		}
+	}
+
+	if *stateFile != "" {
+		latest, err := latestDate(debates, cfg.parse.Location)
+
+		if err != nil {
+			exitOnDataError(err)
+			printError(err)
+			exit(1)
+		}
+
+		if latest.After(state.LastDate) {
+			if err = writeState(*stateFile, runState{LastDate: latest}); err != nil {
+				printError(err)
+				exit(1)
+			}
+		}
+	}
+
+	if *manifestFile != "" {
+		manifest, err := buildManifest(inputs, outputFile, &debates)
+
+		if err != nil {
+			printError(err)
+			exit(1)
+		}
+
+		if err = writeManifest(*manifestFile, manifest); err != nil {
+			printError(err)
+			exit(1)
+		}
+	}
+
+}
+
+// processFile runs the full pipeline for one input file. It returns the parsed debates so callers can report on them.
+func processFile(cfg *config, inputFile string, outputFile string) ([]Debate, error) {
+
+	csvFile, err := readCsv(inputFile, cfg.dialect)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return processRecords(cfg, inputFile, outputFile, csvFile)
+}
+
+// processRecords runs the pipeline on already-read CSV records: parse, sort, summarize and write the output. Several
+// datasets are merged into one summary. The input file name is only used to describe the source. It returns the
+// parsed debates so callers can report on them.
+func processRecords(cfg *config, inputFile string, outputFile string, datasets ...[][]string) ([]Debate, error) {
+
+	debates, err := mergeDebates(cfg.parse, datasets...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.selfCheck {
+		if err = reconcileCounts(debates, cfg.parse, datasets...); err != nil {
+			return nil, err
+		}
+	}
+
+	return processDebates(cfg, inputFile, outputFile, debates)
+}
+
+// processDebates runs the rest of the pipeline on parsed debates: filter, sort, summarize and write the output
+func processDebates(cfg *config, inputFile string, outputFile string, debates []Debate) ([]Debate, error) {
+
+	var err error
+
+	if cfg.roster != nil {
+		missing, extra := compareRoster(&debates, cfg.roster)
+
+		if len(missing) > 0 {
+			warnf("candidates missing from the input: %v", strings.Join(missing, ", "))
+		}
+
+		if len(extra) > 0 {
+			warnf("candidates not on the roster: %v", strings.Join(extra, ", "))
+		}
+
+		if cfg.strictRoster && len(missing)+len(extra) > 0 {
+			return nil, fmt.Errorf("the input's candidates do not match the roster (%d missing, %d extra)", len(missing), len(extra))
+		}
+	}
+
+	if cfg.normalizeDates || cfg.strictDates {
+		if err = normalizeDates(debates, cfg.parse.Location, cfg.normalizeDates, cfg.strictDates); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.dedupeDates {
+		var dropped []string
+
+		if debates, dropped = dedupeDates(debates, cfg.parse.Location); len(dropped) > 0 {
+			warnf("dropped %d debates whose date was already seen: %v", len(dropped), strings.Join(dropped, ", "))
+		}
+	}
+
+	// A debate with a date but no transcribed issues is kept as all-zero placeholder rows unless asked otherwise
+	if !cfg.keepEmptyDebates {
+		debates = dropEmptyDebates(debates)
+	}
+
+	if !cfg.from.IsZero() || !cfg.to.IsZero() {
+		if debates, err = debatesInRange(debates, cfg.from, cfg.to, cfg.parse.Location); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.incremental {
+		if debates, err = debatesSince(debates, cfg.since, cfg.parse.Location); err != nil {
+			return nil, err
+		}
+	}
+
+	// Running totals only make sense in date order, so cumulative mode always sorts
+	if cfg.summary.Cumulative || cfg.debateOrder == debateOrderDate {
+		if err = sortDebatesByDate(&debates, cfg.parse.Location); err != nil {
+			return nil, err
+		}
+	}
+
+	// Excluded issues leave the debates themselves, so no output format or report can mention them
+	if len(cfg.summary.ExcludeIssues) > 0 {
+		excludeIssues(&debates, cfg.summary.ExcludeIssues)
+	}
+
+	if cfg.fuzzyMerge > 0 {
+		for _, merge := range mergeSimilarIssues(&debates, cfg.fuzzyMerge) {
+			notef("fuzzy-merged issue '%v' into '%v' (distance %d)", merge.from, merge.into, merge.distance)
+		}
+	}
+
+	if len(cfg.candidates) > 0 {
+		for _, name := range selectCandidates(&debates, cfg.candidates) {
+			warnf("no candidate named '%v' in the input", name)
+		}
+	}
+
+	if cfg.groups != nil {
+		groupCandidates(&debates, cfg.groups)
+	}
+
+	if len(cfg.redactIssues) > 0 {
+		redacted := redactIssues(&debates, cfg.redactIssues, cfg.redactFold)
+		notef("redacted issues: %v", strings.Join(redacted, ", "))
+	}
+
+	if cfg.hashCandidates {
+		hashCandidateNames(&debates, cfg.salt)
+	}
+
+	sortCandidates(&debates, cfg.sortMode)
+
+	if cfg.summaryTop > 0 {
+		printDigest(os.Stderr, &debates, cfg.summaryTop)
+	}
+
+	if err = checkClobber(outputFile, cfg.noClobber, cfg.interactive); err != nil {
+		return nil, err
+	}
+
+	if err = ensureOutputDir(outputFile, cfg.mkdir); err != nil {
+		return nil, err
+	}
+
+	var summary [][]string
+
+	switch {
+	case len(cfg.highlights) > 0:
+		var empty []highlight
+		summary, empty = summarizeHighlights(&debates, cfg.highlights)
+
+		for _, h := range empty {
+			notef("no candidate discussed '%v' at least %v times", h.Issue, debatedata.FormatWeighted(h.Threshold))
+		}
+	case cfg.topN > 0:
+		summary = summarizeTopN(&debates, cfg.topN)
+	case cfg.report == reportIssues:
+		summary = summarizeIssueRanking(&debates)
+	case cfg.report == reportDupes:
+		summary = summarizeDuplicates(&debates, cfg.dupeThreshold)
+	case cfg.report == reportTrends:
+		summary, err = summarizeTrends(&debates, cfg.parse.Location)
+	case cfg.report == reportWeighted:
+		summary = summarizeWeightedScores(&debates, cfg.issueWeights, cfg.summary.GroupColumn)
+	case cfg.report == reportCooccurrence:
+		summary = summarizeCooccurrence(&debates)
+	case cfg.compare[0] != "":
+		summary, err = summarizeComparison(&debates, cfg.compare[0], cfg.compare[1])
+	case cfg.tableFormat():
+		if cfg.aggregate {
+			summary, err = summarizeAggregate(&debates, cfg.summary, cfg.sortMode)
+		} else {
+			summary, err = debatedata.Summarize(&debates, cfg.summary)
+		}
+
+		if err == nil && cfg.sumOnly {
+			summary, err = totalsOnly(summary, cfg.sumPercent)
+		}
+
+		if err == nil && cfg.diffFile != "" {
+			var previous [][]string
+
+			if previous, err = readCsv(cfg.diffFile, cfg.dialect); err == nil {
+				summary, err = diffSummaries(previous, summary, cfg.diffThreshold)
+			}
+		}
+
+		if err == nil && cfg.candidatesFooter {
+			summary = append(summary, candidatesFooter(summary[0], &debates))
+		}
+	case cfg.format == "count+percent":
+		if cfg.aggregate {
+			summary, err = summarizeAggregate(&debates, cfg.summary, cfg.sortMode)
+		} else {
+			summary, err = debatedata.Summarize(&debates, cfg.summary)
+		}
+
+		if err == nil {
+			summary, err = withPercentages(summary)
+		}
+
+		if err == nil && cfg.candidatesFooter {
+			summary = append(summary, candidatesFooter(summary[0], &debates))
+		}
+	case cfg.format == "diversity":
+		summary = summarizeDiversity(&debates)
+	case cfg.format == "ranks":
+		summary = summarizeRanks(&debates, cfg.rankZeros == "blank", cfg.summary.GroupColumn)
+	case cfg.format == "ndjson":
+		err = writeNdjson(outputFile, &debates)
+	case cfg.format == "json":
+		err = writeJson(outputFile, &debates, cfg.compactJson)
+	case cfg.format == "issuejson":
+		err = writeIssueJson(outputFile, &debates, cfg.compactJson)
+	case cfg.format == "summaryjson":
+		var matrix [][]string
+
+		if matrix, err = debatedata.Summarize(&debates, cfg.summary); err == nil {
+			err = writeSummaryJson(outputFile, matrix, cfg.compactJson)
+		}
+	case cfg.format == "parquet":
+		err = writeParquet(outputFile, &debates, cfg.parse.Location)
+	default:
+		err = fmt.Errorf("unknown output format '%v'", cfg.format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Count matrices end with a Total row that is kept, and still reflects every row, when truncating
+	var footers = 0
+
+	if len(cfg.highlights) == 0 && cfg.topN == 0 && cfg.compare[0] == "" && cfg.report == "" && !cfg.sumOnly && (cfg.tableFormat() || cfg.format == "count+percent") {
+		footers = 1
+
+		if cfg.candidatesFooter {
+			footers = 2
+		}
+	}
+
+	if cfg.report == reportWeighted {
+		footers = 1
+	}
+
+	if summary != nil && cfg.limitRows > 0 {
+		var dropped int
+		summary, dropped = limitRows(summary, cfg.limitRows, footers)
+
+		if dropped > 0 {
+			warnf("output truncated to %d rows, %d more not shown", cfg.limitRows, dropped)
+		}
+	}
+
+	if summary != nil && cfg.locale != "" {
+		tag, err := language.Parse(cfg.locale)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid -locale '%v': %v", cfg.locale, err)
+		}
+
+		// Only the value columns are localized; dates and names are left as they are
+		var firstValueColumn = debatedata.FirstIssueColumn(summary[0])
+
+		switch {
+		case len(cfg.highlights) > 0:
+			firstValueColumn = 4
+		case cfg.compare[0] != "" || cfg.report != "":
+			firstValueColumn = 1
+		case cfg.sumOnly:
+			firstValueColumn = 0
+		}
+
+		localizeNumbers(summary, tag, firstValueColumn, cfg.localeGrouping)
+	}
+
+	// The legend of shortened issue labels is kept apart from the table: markdown and html set it below as a table of
+	// its own, while CSV output leaves it to stderr so the file stays machine-readable
+	var legend [][]string
+
+	if summary != nil && cfg.maxIssueWidth > 0 && len(cfg.highlights) == 0 && cfg.topN == 0 && cfg.compare[0] == "" {
+		legend = shortenIssueLabels(summary, debatedata.FirstIssueColumn(summary[0]), cfg.maxIssueWidth)
+
+		if cfg.format == "count+percent" {
+			for _, entry := range legend {
+				notef("issue label '%v' is short for '%v'", entry[0], entry[1])
+			}
+		}
+	}
+
+	if summary != nil && cfg.transpose {
+		summary = transpose(summary)
+	}
+
+	if summary != nil && cfg.labels != (columnLabels{date: "Date", candidate: "Candidate", total: "Total"}) {
+		relabelColumns(summary, cfg.labels)
+	}
+
+	if summary != nil && cfg.format == "markdown" {
+		if err = writeMarkdown(outputFile, summary, legend); err != nil {
+			return nil, err
+		}
+	} else if summary != nil && cfg.format == "html" {
+		// Transposing moves the totals into the last column, so only untransposed footers stay at the bottom
+		if cfg.transpose {
+			footers = 0
+		}
+
+		if err = writeHtml(outputFile, summary, footers, cfg.htmlSortable, legend); err != nil {
+			return nil, err
+		}
+	} else if summary != nil && cfg.format == "xlsx" {
+		var sheets = []xlsxSheet{{name: "Summary", rows: summary}}
+
+		if cfg.xlsxDebateSheets {
+			var perDebate []xlsxSheet
+
+			if perDebate, err = debateSheets(&debates, cfg.summary); err != nil {
+				return nil, err
+			}
+
+			for _, sheet := range perDebate {
+				relabelColumns(sheet.rows, cfg.labels)
+			}
+
+			sheets = append(sheets, perDebate...)
+		}
+
+		if err = writeXlsx(outputFile, sheets); err != nil {
+			return nil, err
+		}
+	} else if summary != nil {
+		var comment string
+
+		if cfg.headerComment {
+			comment = fmt.Sprintf("%c generated %v from %v", cfg.commentChar(), time.Now().UTC().Format(time.RFC3339), inputFile)
+		}
+
+		if cfg.outputEncoding != nil {
+			var lines = [][]string{{comment}}
+
+			if err = encodeCells(summary, cfg.outputEncoding, cfg.transliterate); err == nil {
+				err = encodeCells(lines, cfg.outputEncoding, cfg.transliterate)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			comment = lines[0][0]
+		}
+
+		if err = writeCsv(outputFile, comment, summary, cfg.dialect.comma, cfg.outputEncoding); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.checksum != "" {
+		if err = writeChecksum(outputFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return debates, nil
+}
+
+// listCandidates prints the sorted, deduplicated candidate names found across all debates. When verbose is set each
+// name is followed by the number of debates in which that candidate discussed at least one issue.
+func listCandidates(w io.Writer, debates *[]Debate, verbose bool) {
+
+	var participation = make(map[string]int)
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			if candidateTotal(candidate) > 0 {
+				participation[candidate.Name]++
+			} else if _, exists := participation[candidate.Name]; !exists {
+				participation[candidate.Name] = 0
+			}
+		}
+	}
+
+	var names []string
+
+	for name := range participation {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if verbose {
+			fmt.Fprintf(w, "%v\t%d debates\n", name, participation[name])
+		} else {
+			fmt.Fprintln(w, name)
+		}
+	}
+}
+
+// processDir summarizes every *.csv file in inDir separately, writing each summary to outDir under the input's file
+// name. When continueOnError is set a failing file doesn't stop the batch; every failure is reported together at the
+// end instead. It returns the inputs that were processed successfully and their combined debates.
+func processDir(cfg *config, inDir string, outDir string, continueOnError bool) ([]string, []Debate, error) {
+
+	files, err := filepath.Glob(filepath.Join(inDir, "*.csv"))
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not list input directory: %v", err)
+	}
+
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no *.csv files found in '%v'", inDir)
+	}
+
+	var inputs []string
+	var allDebates []Debate
+	var failures []string
+
+	for _, file := range files {
+		debates, err := processFile(cfg, file, filepath.Join(outDir, filepath.Base(file)))
+
+		if err != nil {
+			if !continueOnError {
+				return nil, nil, fmt.Errorf("%v: %v", file, err)
+			}
+
+			failures = append(failures, fmt.Sprintf("%v: %v", file, err))
+			continue
+		}
+
+		inputs = append(inputs, file)
+		allDebates = append(allDebates, debates...)
+	}
+
+	if len(failures) > 0 {
+		return nil, nil, fmt.Errorf("%d of %d files failed:\n  %v", len(failures), len(files), strings.Join(failures, "\n  "))
+	}
+
+	return inputs, allDebates, nil
+}
+
+// exitOnDataError prints a DataError to stderr and exits with a non-zero status. Other errors are left to the caller.
+func exitOnDataError(err error) {
+	var dataErr *DataError
+
+	if errors.As(err, &dataErr) {
+		printError(dataErr)
+		exit(1)
+	}
+}
+
+// candidatesFooter builds a footer row labeled "Candidates" holding, for each issue column of header, the number of
+// distinct candidates who discussed that issue at least once across all debates
+func candidatesFooter(header []string, debates *[]Debate) []string {
+
+	first := debatedata.FirstIssueColumn(header)
+	speakers := make(map[string]map[string]interface{})
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			for issue, count := range candidate.IssueCount {
+				if count == 0 {
+					continue
+				}
+
+				if speakers[issue] == nil {
+					speakers[issue] = make(map[string]interface{})
+				}
+
+				speakers[issue][candidate.Name] = nil
+			}
+		}
+	}
+
+	var footer = make([]string, len(header))
+
+	footer[first-1] = "Candidates"
+
+	var issues = make(map[string]interface{})
+
+	for _, issue := range debatedata.GetIssues(debates) {
+		issues[issue] = nil
+	}
+
+	// Columns that aren't issues, such as a row Total column, are left blank
+	for colNum := first; colNum < len(header); colNum++ {
+		if _, exists := issues[header[colNum]]; exists {
+			footer[colNum] = strconv.Itoa(len(speakers[header[colNum]]))
+		}
+	}
+
+	return footer
+}
+
+// limitRows truncates a matrix to its header and the first limit data rows, keeping the last footers rows (such as
+// the Total row) as well. It returns the truncated matrix and the number of rows dropped.
+func limitRows(rows [][]string, limit int, footers int) ([][]string, int) {
+
+	data := rows[1:]
+	footer := data[len(data)-footers:]
+	data = data[:len(data)-footers]
+
+	if len(data) <= limit {
+		return rows, 0
+	}
+
+	var limited = append([][]string{rows[0]}, data[:limit]...)
+
+	return append(limited, footer...), len(data) - limit
+}
+
+// withPercentages renders every issue cell as "COUNT (SHARE%)". In candidate rows the share is of that candidate's
+// mentions in the debate; in the Total row it is the issue's share of all mentions. This is a human-readable format
+// for reports: the cells are no longer plain numbers, so it is not meant for machine ingestion.
+func withPercentages(summary [][]string) ([][]string, error) {
+
+	var rows = [][]string{summary[0]}
+	first := debatedata.FirstIssueColumn(summary[0])
+
+	for rowNum, row := range summary[1:] {
+		var values = make([]float64, len(row))
+		var rowTotal = 0.0
+
+		// Start after the key columns (date, any group column, and candidate)
+		for colNum := first; colNum < len(row); colNum++ {
+			val, err := strconv.ParseFloat(row[colNum], 64)
+
+			if err != nil {
+				return nil, &DataError{
+					Row:     rowNum + 1,
+					Column:  colNum,
+					Kind:    InvalidCount,
+					Message: fmt.Sprintf("'%v' is not a valid issue count", row[colNum]),
+				}
+			}
+
+			values[colNum] = val
+			rowTotal += val
+		}
+
+		var formatted = append([]string{}, row[:first]...)
+
+		for colNum := first; colNum < len(row); colNum++ {
+			var share = 0.0
+
+			if rowTotal != 0 {
+				share = values[colNum] / rowTotal * 100
+			}
+
+			formatted = append(formatted, fmt.Sprintf("%v (%.1f%%)", row[colNum], share))
+		}
+
+		rows = append(rows, formatted)
+	}
+
+	return rows, nil
+}
+
+// totalsOnly reduces a summary matrix to two rows: the issue header and the grand-total row, without the Date and
+// Candidate columns. When percent is set each total is replaced by its share of all mentions, to one decimal place.
+func totalsOnly(summary [][]string, percent bool) ([][]string, error) {
+
+	first := debatedata.FirstIssueColumn(summary[0])
+	header := summary[0][first:]
+	totals := append([]string{}, summary[len(summary)-1][first:]...)
+
+	if percent {
+		var values = make([]float64, len(totals))
+		var grandTotal = 0.0
+
+		for k, total := range totals {
+			val, err := strconv.ParseFloat(total, 64)
+
+			if err != nil {
+				return nil, &DataError{
+					Row:     len(summary) - 1,
+					Column:  k + first,
+					Kind:    InvalidCount,
+					Message: fmt.Sprintf("'%v' is not a valid issue total", total),
+				}
+			}
+
+			values[k] = val
+			grandTotal += val
+		}
+
+		for k, val := range values {
+			var share = 0.0
+
+			if grandTotal != 0 {
+				share = val / grandTotal * 100
+			}
+
+			totals[k] = strconv.FormatFloat(share, 'f', 1, 64)
+		}
+	}
+
+	return [][]string{header, totals}, nil
+}
+
+// summarizeRanks produces the same matrix as debatedata.Summarize, but each issue cell holds the candidate's rank on that issue
+// relative to the other candidates in the same debate (1 = discussed it most). Tied counts share a rank. When
+// blankZeros is set, a candidate who never discussed an issue gets an empty cell instead of the lowest rank.
+func summarizeRanks(debates *[]Debate, blankZeros bool, groupColumn string) [][]string {
+
+	var rows [][]string
+
+	sortedIssues := debatedata.GetIssues(debates)
+
+	header := append(debatedata.KeyColumns(groupColumn), sortedIssues...)
+	first := debatedata.FirstIssueColumn(header)
+
+	rows = append(rows, header)
+
+	for _, debate := range *debates {
+
+		// Rank every issue within this debate before building the candidate rows
+		ranks := make(map[string][]int)
+
+		for _, issue := range sortedIssues {
+			ranks[issue] = rankCounts(debate.Candidates, issue)
+		}
+
+		for ck, candidate := range debate.Candidates {
+
+			var row = make([]string, len(header))
+
+			for hk, h := range header {
+
+				switch {
+				case hk == 0:
+					row[hk] = debate.Date
+				case hk == first-1:
+					row[hk] = candidate.Name
+				case hk < first:
+					row[hk] = debate.Group
+				default:
+					if blankZeros && candidate.IssueCount[h] == 0 {
+						continue
+					}
+					row[hk] = strconv.Itoa(ranks[h][ck])
+				}
+			}
+
+			rows = append(rows, row)
+
+		}
+
+	}
+
+	return rows
+
+}
+
+// rankCounts returns the rank of each candidate's count for the given issue, in the same order as candidates.
+// Ranking uses standard competition ranking, so tied counts share a rank and the next rank is skipped (1, 2, 2, 4).
+func rankCounts(candidates []Candidate, issue string) []int {
+
+	// Sort the counts in descending order so the first position of a count is its rank
+	var sorted = make([]float64, len(candidates))
+
+	for k, candidate := range candidates {
+		sorted[k] = candidate.Score(issue)
+	}
+
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+
+	var ranks = make([]int, len(candidates))
+
+	for k, candidate := range candidates {
+		ranks[k] = sort.Search(len(sorted), func(i int) bool {
+			return sorted[i] <= candidate.Score(issue)
+		}) + 1
+	}
+
+	return ranks
+}
+
+// defaultDisallowIssues matches the placeholder tokens that leak into issue cells from misaligned data: pure
+// numbers, "N/A" and "none"
+const defaultDisallowIssues = `^(?i:\d+(\.\d+)?|n/a|none)$`
+
+// Candidate sort modes control the order of candidate rows within each debate
+const (
+	// sortByName orders candidates alphabetically by name
+	sortByName = "name"
+	// sortByTotal orders candidates by their total issue count in the debate, highest first
+	sortByTotal = "total"
+	// sortNone keeps candidates in input column order
+	sortNone = "none"
+)
+
+// sortCandidates orders the candidates of every debate according to the given sort mode. Ties on total are broken by
+// name so the output is deterministic.
+func sortCandidates(debates *[]Debate, mode string) {
+
+	for _, debate := range *debates {
+		candidates := debate.Candidates
+
+		switch mode {
+		case sortByName:
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return candidates[i].Name < candidates[j].Name
+			})
+		case sortByTotal:
+			sort.SliceStable(candidates, func(i, j int) bool {
+				ti, tj := candidateTotal(candidates[i]), candidateTotal(candidates[j])
+				if ti != tj {
+					return ti > tj
+				}
+				return candidates[i].Name < candidates[j].Name
+			})
+		}
+	}
+}
+
+// dropEmptyDebates returns the debates in which at least one candidate mentioned an issue
+func dropEmptyDebates(debates []Debate) []Debate {
+
+	var kept = make([]Debate, 0, len(debates))
+
+	for _, debate := range debates {
+		for _, candidate := range debate.Candidates {
+			if candidateTotal(candidate) > 0 {
+				kept = append(kept, debate)
+				break
+			}
+		}
+	}
+
+	return kept
+}
+
+// candidateTotal returns the sum of all of a candidate's issue counts
+func candidateTotal(candidate Candidate) int {
+	var total = 0
+
+	for _, count := range candidate.IssueCount {
+		total += count
+	}
+
+	return total
+}
+
+// selectCandidates keeps only the named candidates in every debate. Names may be comma-separated lists and are
+// sanitized like column titles and matched case-insensitively. It returns the requested names that matched no
+// candidate in any debate.
+func selectCandidates(debates *[]Debate, names []string) []string {
+
+	var wanted = make(map[string]string)
+	var order []string
+
+	for _, list := range names {
+		for _, name := range strings.Split(list, ",") {
+			name = debatedata.SanitizeColumnName(name)
+			key := strings.ToLower(name)
+
+			if _, exists := wanted[key]; name != "" && !exists {
+				wanted[key] = name
+				order = append(order, key)
+			}
+		}
+	}
+
+	var found = make(map[string]interface{})
+
+	for k := range *debates {
+		debate := &(*debates)[k]
+		var kept []Candidate
+
+		for _, candidate := range debate.Candidates {
+			key := strings.ToLower(candidate.Name)
+
+			if _, exists := wanted[key]; exists {
+				kept = append(kept, candidate)
+				found[key] = nil
+			}
+		}
+
+		debate.Candidates = kept
+	}
+
+	var unknown []string
+
+	for _, key := range order {
+		if _, exists := found[key]; !exists {
+			unknown = append(unknown, wanted[key])
+		}
+	}
+
+	return unknown
+}
+
+// redactedIssue is the column that receives redacted counts when they are folded rather than dropped
+const redactedIssue = "Redacted"
+
+// redactIssues removes the named issues (matched case-insensitively) from every candidate's IssueCount. When fold is
+// set their counts are moved into a single redactedIssue entry instead of being discarded. It returns the sorted names
+// of the issues that were actually found and redacted.
+func redactIssues(debates *[]Debate, names []string, fold bool) []string {
+
+	var found = make(map[string]interface{})
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			for issue, count := range candidate.IssueCount {
+				for _, name := range names {
+					if !strings.EqualFold(issue, strings.TrimSpace(name)) {
+						continue
+					}
+
+					found[issue] = nil
+					delete(candidate.IssueCount, issue)
+
+					if fold {
+						candidate.IssueCount[redactedIssue] += count
+					}
+
+					if candidate.WeightedCount != nil {
+						if fold {
+							candidate.WeightedCount[redactedIssue] += candidate.WeightedCount[issue]
+						}
+						delete(candidate.WeightedCount, issue)
+					}
+
+					break
+				}
+			}
+		}
+	}
+
+	var redacted []string
+
+	for issue := range found {
+		redacted = append(redacted, issue)
+	}
+
+	sort.Strings(redacted)
+
+	return redacted
+}
+
+// excludeIssues removes the named issues (matched case-insensitively) from every candidate's counts, per-round counts
+// and cell issues. Cells left without any issue are dropped.
+func excludeIssues(debates *[]Debate, names []string) {
+
+	var excluded = func(issue string) bool {
+		for _, name := range names {
+			if strings.EqualFold(issue, strings.TrimSpace(name)) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, debate := range *debates {
+		for k := range debate.Candidates {
+			candidate := &debate.Candidates[k]
+
+			for issue := range candidate.IssueCount {
+				if excluded(issue) {
+					delete(candidate.IssueCount, issue)
+					delete(candidate.WeightedCount, issue)
+				}
+			}
+
+			for _, counts := range candidate.RoundCounts {
+				for issue := range counts {
+					if excluded(issue) {
+						delete(counts, issue)
+					}
+				}
+			}
+
+			var cells [][]string
+
+			for _, cell := range candidate.CellIssues {
+				var kept []string
+
+				for _, issue := range cell {
+					if !excluded(issue) {
+						kept = append(kept, issue)
+					}
+				}
+
+				if len(kept) > 0 {
+					cells = append(cells, kept)
+				}
+			}
+
+			candidate.CellIssues = cells
+		}
+	}
+}
+
+// hashCandidateNames pseudonymizes every candidate name as the first 8 hex characters of the SHA-256 of the salt
+// followed by the name. The same name always maps to the same token, so a candidate stays recognizable across debates.
+func hashCandidateNames(debates *[]Debate, salt string) {
+
+	for _, debate := range *debates {
+		for k, candidate := range debate.Candidates {
+			sum := sha256.Sum256([]byte(salt + candidate.Name))
+			debate.Candidates[k].Name = hex.EncodeToString(sum[:])[:8]
+		}
+	}
+}
+
+// parseRoundWeights parses a round weight list such as "1:1,2:1.5,3:2" into a map of round number to weight
+func parseRoundWeights(val string) (map[int]float64, error) {
+
+	var weights = make(map[int]float64)
+
+	for _, pair := range strings.Split(val, ",") {
+		parts := strings.Split(strings.TrimSpace(pair), ":")
+
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid round weight '%v': expected ROUND:WEIGHT", pair)
+		}
+
+		round, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid round number in '%v': %v", pair, err)
+		}
+
+		weight, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight in '%v': %v", pair, err)
+		}
+
+		weights[round] = weight
+	}
+
+	return weights, nil
+}
+
+// writeCsv is a helper function that writes data to a CSV file, or to stdout when the name is "-". A non-empty
+// comment is written as its own line before the CSV records, since csv.Writer has no notion of comments. Fields are
+// separated by comma, or ',' when it is 0.
+func writeCsv(fileName string, comment string, data [][]string, comma rune, enc encoding.Encoding) error {
+	var f = os.Stdout
+
+	if fileName != stdio {
+		var err error
+
+		if f, err = os.Create(fileName); err != nil {
+			return fmt.Errorf("could not open csv: %v", err)
+		}
+
+		defer func(f *os.File) {
+			err := f.Close()
+			if err != nil {
+
+			}
+		}(f)
+	}
+
+	if err := writeCsvTo(f, comment, data, comma, enc); err != nil {
+		return fmt.Errorf("could not write to csv file '%v': %v", fileName, err)
+	}
+
+	return nil
+
+}
+
+// writeCsvTo writes the comment line, if any, and the CSV records to out, the same way writeCsv does for a file
+func writeCsvTo(out io.Writer, comment string, data [][]string, comma rune, enc encoding.Encoding) error {
+
+	// Output in another charset goes through an encoder; nil writes UTF-8 as is
+	var w = out
+	var encoder io.WriteCloser
+
+	if enc != nil {
+		encoder = transform.NewWriter(out, enc.NewEncoder())
+		w = encoder
+	}
+
+	if comment != "" {
+		if _, err := fmt.Fprintln(w, comment); err != nil {
+			return err
+		}
+	}
+
+	csvWriter := csv.NewWriter(w)
+
+	if comma != 0 {
+		csvWriter.Comma = comma
+	}
+
+	err := csvWriter.WriteAll(data)
+
+	if err == nil && encoder != nil {
+		// Closing the encoder flushes whatever it still buffers to the writer
+		err = encoder.Close()
+	}
+
+	return err
+}
+
+// checkClobber guards an existing output file. With noClobber it refuses to overwrite; with interactive it asks the
+// user on the terminal and refuses unless they answer yes. Missing files and the default mode always pass.
+func checkClobber(fileName string, noClobber bool, interactive bool) error {
+
+	if !noClobber && !interactive {
+		return nil
+	}
+
+	if _, err := os.Stat(fileName); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if noClobber {
+		return fmt.Errorf("output file '%v' already exists (remove it or drop -no-clobber)", fileName)
+	}
+
+	// Only prompt when someone is there to answer; otherwise err on the side of keeping the file
+	info, err := os.Stdin.Stat()
+
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("output file '%v' already exists and stdin is not a terminal to confirm overwriting", fileName)
+	}
+
+	fmt.Fprintf(os.Stderr, "overwrite '%v'? [y/N] ", fileName)
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	if answer != "y" && answer != "yes" {
+		return fmt.Errorf("not overwriting '%v'", fileName)
+	}
+
+	return nil
+}
+
+// ensureOutputDir checks that the directory an output file will be written to exists. With mkdir it creates the
+// missing directory tree instead of failing.
+func ensureOutputDir(fileName string, mkdir bool) error {
+
+	dir := filepath.Dir(fileName)
+
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if !mkdir {
+		return fmt.Errorf("output directory does not exist: create it or pass -mkdir (%v)", dir)
+	}
+
+	return os.MkdirAll(dir, 0755)
+}
+
+// writeChecksum writes the SHA-256 of a file to a ".sha256" file next to it, in the "HASH  NAME" format that
+// `sha256sum -c` expects. The name is relative to the checksum file so the pair can be moved together.
+func writeChecksum(fileName string) error {
+	file, err := hashFile(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	line := fmt.Sprintf("%v  %v\n", file.SHA256, filepath.Base(fileName))
+
+	if err = os.WriteFile(fileName+".sha256", []byte(line), 0644); err != nil {
+		return fmt.Errorf("could not write checksum file: %v", err)
+	}
+
+	return nil
+}
+
+// csvDialect describes the CSV conventions of the input files
+type csvDialect struct {
+	// comment starts a comment line that the reader skips; 0 disables comments
+	comment rune
+	// comma separates fields in the input and in CSV output; 0 means ','
+	comma rune
+	// encoding is the charset the input is decoded from; nil reads UTF-8
+	encoding encoding.Encoding
+}
+
+// newReader returns a csv.Reader for r configured for the dialect
+func (d csvDialect) newReader(r io.Reader) *csv.Reader {
+	if d.encoding != nil {
+		r = transform.NewReader(r, d.encoding.NewDecoder())
+	}
+
+	// Files saved by Excel on Windows start with a UTF-8 byte-order mark, which would otherwise stick to the first
+	// header cell and hide the Date column
+	br := bufio.NewReader(r)
+
+	if bom, err := br.Peek(len(utf8BOM)); err == nil && string(bom) == utf8BOM {
+		br.Discard(len(utf8BOM))
+	}
+
+	csvReader := csv.NewReader(br)
+	csvReader.Comment = d.comment
+	// Rows with the wrong number of columns are reported by ParseCSVData, which knows the row's place in the data
+	csvReader.FieldsPerRecord = -1
+
+	if d.comma != 0 {
+		csvReader.Comma = d.comma
+	}
+
+	return csvReader
+}
+
+// utf8BOM is the byte-order mark some editors write at the start of UTF-8 files
+const utf8BOM = "\uFEFF"
+
+// stdio is the file name that stands for stdin when reading and stdout when writing
+const stdio = "-"
+
+// readCsv is a helper function which reads data from a CSV file, or from stdin when the name is "-"
+func readCsv(fileName string, dialect csvDialect) ([][]string, error) {
+	if fileName == stdio {
+		return readCsvFrom(os.Stdin, dialect)
+	}
+
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not open csv: %v", err)
+	}
+
+	defer func(f *os.File) {
+		err := f.Close()
+		if err != nil {
+
+		}
+	}(f)
+
+	return readCsvFrom(f, dialect)
+}
+
+// readCsvFrom reads every record from r
+func readCsvFrom(r io.Reader, dialect csvDialect) ([][]string, error) {
+	csvReader := dialect.newReader(r)
+	records, err := csvReader.ReadAll()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read csv: %v", err)
+	}
+
+	return records, nil
+
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"debateData/debatedata"
+)
+
+// runMain runs the command with args in a child process and returns its exit code and standard error
+func runMain(t *testing.T, args ...string) (int, string) {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMainProcess$")
+	cmd.Env = append(os.Environ(), "DEBATEDATA_RUN_MAIN=1", "DEBATEDATA_ARGS="+strings.Join(args, "\n"))
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), stderr.String()
+	}
+
+	if err != nil {
+		t.Fatalf("running main: %v", err)
+	}
+
+	return 0, stderr.String()
+}
+
+// TestMainProcess is the child process of runMain, running main with the arguments in DEBATEDATA_ARGS
+func TestMainProcess(t *testing.T) {
+
+	if os.Getenv("DEBATEDATA_RUN_MAIN") != "1" {
+		t.Skip("only runs as the child process of runMain")
+	}
+
+	os.Args = append([]string{"debatedata"}, strings.Split(os.Getenv("DEBATEDATA_ARGS"), "\n")...)
+	main()
+	os.Exit(0)
+}
+
+// summarizeText reads CSV text in the given dialect and returns its default summary
+func summarizeText(t *testing.T, text string, dialect csvDialect) [][]string {
+	t.Helper()
+
+	records, err := readCsvFrom(strings.NewReader(text), dialect)
+
+	if err != nil {
+		t.Fatalf("readCsvFrom: %v", err)
+	}
+
+	debates, err := debatedata.ParseCSVData(records, debatedata.ParseOptions{})
+
+	if err != nil {
+		t.Fatalf("ParseCSVData: %v", err)
+	}
+
+	summary, err := debatedata.Summarize(&debates, debatedata.SummaryOptions{})
+
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+
+	return summary
+}
+
+// TestTabDelimitedInput checks that a tab-separated file summarizes the same as its comma-separated equivalent, and
+// that the summary is written back out with tabs
+func TestTabDelimitedInput(t *testing.T) {
+
+	comma := "Date,Candidate A [1],Candidate A [2],Candidate B [1]\n" +
+		"1/1/2021,\"Economy, Jobs\",Healthcare,Education\n" +
+		"2/1/2021,Jobs,,\"Economy, Climate\"\n"
+
+	tab := "Date\tCandidate A [1]\tCandidate A [2]\tCandidate B [1]\n" +
+		"1/1/2021\tEconomy, Jobs\tHealthcare\tEducation\n" +
+		"2/1/2021\tJobs\t\tEconomy, Climate\n"
+
+	want := summarizeText(t, comma, csvDialect{})
+	got := summarizeText(t, tab, csvDialect{comma: '\t'})
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tab-separated summary = %q, want %q", got, want)
+	}
+
+	var out bytes.Buffer
+
+	if err := writeCsvTo(&out, "", got, '\t', nil); err != nil {
+		t.Fatalf("writeCsvTo: %v", err)
+	}
+
+	if header := strings.SplitN(out.String(), "\n", 2)[0]; header != "Date\tCandidate\tClimate\tEconomy\tEducation\tHealthcare\tJobs" {
+		t.Errorf("written header = %q", header)
+	}
+}
+
+// TestSortCandidates checks the row order of each -sort mode: within each debate, debates staying in input order, and
+// the Total row last
+func TestSortCandidates(t *testing.T) {
+
+	data := [][]string{
+		{"Date", "Carol [1]", "Alice [1]", "Bob [1]", "Bob [2]"},
+		{"1/1/2021", "Economy", "Jobs, Healthcare", "Economy, Jobs", "Climate"},
+		{"2/1/2021", "Economy, Jobs, Climate", "", "Healthcare", "Jobs"},
+	}
+
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		{sortByName, []string{"1/1/2021 Alice", "1/1/2021 Bob", "1/1/2021 Carol", "2/1/2021 Alice", "2/1/2021 Bob", "2/1/2021 Carol", " Total"}},
+		{sortByTotal, []string{"1/1/2021 Bob", "1/1/2021 Alice", "1/1/2021 Carol", "2/1/2021 Carol", "2/1/2021 Bob", "2/1/2021 Alice", " Total"}},
+		{sortNone, []string{"1/1/2021 Carol", "1/1/2021 Alice", "1/1/2021 Bob", "2/1/2021 Carol", "2/1/2021 Alice", "2/1/2021 Bob", " Total"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.mode, func(t *testing.T) {
+			debates, err := debatedata.ParseCSVData(data, debatedata.ParseOptions{})
+
+			if err != nil {
+				t.Fatalf("ParseCSVData: %v", err)
+			}
+
+			sortCandidates(&debates, test.mode)
+
+			summary, err := debatedata.Summarize(&debates, debatedata.SummaryOptions{})
+
+			if err != nil {
+				t.Fatalf("Summarize: %v", err)
+			}
+
+			var got []string
+
+			for _, row := range summary[1:] {
+				got = append(got, row[0]+" "+row[1])
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("rows = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// TestReadCsvStripsBOM checks that a byte-order mark before the header doesn't hide the Date column
+func TestReadCsvStripsBOM(t *testing.T) {
+
+	text := utf8BOM + "Date,Candidate A [1]\n1/1/2021,Economy\n"
+
+	records, err := readCsvFrom(strings.NewReader(text), csvDialect{})
+
+	if err != nil {
+		t.Fatalf("readCsvFrom: %v", err)
+	}
+
+	if records[0][0] != "Date" {
+		t.Fatalf("first header cell = %q, want %q", records[0][0], "Date")
+	}
+
+	debates, err := debatedata.ParseCSVData(records, debatedata.ParseOptions{})
+
+	if err != nil {
+		t.Fatalf("ParseCSVData: %v", err)
+	}
+
+	if len(debates) != 1 || debates[0].Date != "1/1/2021" {
+		t.Errorf("ParseCSVData = %+v, want one debate dated 1/1/2021", debates)
+	}
+
+	// The summary of a file with a BOM is the same as one without
+	if got, want := summarizeText(t, text, csvDialect{}), summarizeText(t, text[len(utf8BOM):], csvDialect{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("summary with BOM = %q, want %q", got, want)
+	}
+}
+
+// TestSumOnlyWithDiffRejected checks that -sum-only and -diff together fail before any output is written, since the
+// diff needs the Date and Candidate columns that -sum-only drops
+func TestSumOnlyWithDiffRejected(t *testing.T) {
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "debates.csv")
+	previous := filepath.Join(dir, "previous.csv")
+	output := filepath.Join(dir, "output.csv")
+
+	for _, name := range []string{input, previous} {
+		if err := os.WriteFile(name, []byte("Date,Candidate A [1]\n1/1/2021,\"Economy, Jobs, Healthcare\"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	code, stderr := runMain(t, "-sum-only", "-diff", previous, "-out", output, input)
+
+	if code != 1 {
+		t.Fatalf("exit code = %d, want 1 (stderr %q)", code, stderr)
+	}
+
+	if !strings.Contains(stderr, "-sum-only cannot be combined with -diff") {
+		t.Errorf("stderr = %q, want the -sum-only/-diff error", stderr)
+	}
+
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("output file was written (stat error %v)", err)
+	}
+}
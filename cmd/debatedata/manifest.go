@@ -0,0 +1,124 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"debateData/debatedata"
+)
+
+// version is the tool version recorded in manifests. Release builds override it with
+// `go build -ldflags "-X main.version=v1.2.3"`.
+var version = "dev"
+
+// Manifest records how a report was generated so a published output can be audited and reproduced
+type Manifest struct {
+	Version     string            `json:"version"`
+	GeneratedAt string            `json:"generatedAt"`
+	Inputs      []ManifestFile    `json:"inputs"`
+	Output      string            `json:"output"`
+	Flags       map[string]string `json:"flags"`
+	Debates     int               `json:"debates"`
+	Candidates  int               `json:"candidates"`
+	Issues      int               `json:"issues"`
+}
+
+// ManifestFile describes one input file used to build a report. URL and stdin inputs are recorded without a size or
+// hash.
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// buildManifest collects the metadata for a completed run: the hashed inputs, the resolved value of every flag and
+// the size of the parsed data set
+func buildManifest(inputs []string, output string, debates *[]Debate) (*Manifest, error) {
+
+	var manifest = Manifest{
+		Version:     version,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Output:      output,
+		Flags:       make(map[string]string),
+		Debates:     len(*debates),
+		Issues:      len(debatedata.GetIssues(debates)),
+	}
+
+	for _, input := range inputs {
+		if isURL(input) || input == stdio {
+			manifest.Inputs = append(manifest.Inputs, ManifestFile{Path: input})
+			continue
+		}
+
+		file, err := hashFile(input)
+
+		if err != nil {
+			return nil, err
+		}
+
+		manifest.Inputs = append(manifest.Inputs, file)
+	}
+
+	// Record every flag, not just the ones that were set, so the manifest captures the full resolved config
+	flag.VisitAll(func(f *flag.Flag) {
+		manifest.Flags[f.Name] = f.Value.String()
+	})
+
+	var candidates = make(map[string]interface{})
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			candidates[candidate.Name] = nil
+		}
+	}
+
+	manifest.Candidates = len(candidates)
+
+	return &manifest, nil
+}
+
+// hashFile returns the size and SHA-256 digest of a file
+func hashFile(fileName string) (ManifestFile, error) {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return ManifestFile{}, fmt.Errorf("could not open '%v' for hashing: %v", fileName, err)
+	}
+
+	defer func(f *os.File) {
+		err := f.Close()
+		if err != nil {
+
+		}
+	}(f)
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+
+	if err != nil {
+		return ManifestFile{}, fmt.Errorf("could not hash '%v': %v", fileName, err)
+	}
+
+	return ManifestFile{Path: fileName, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// writeManifest writes the manifest to a file as indented JSON
+func writeManifest(fileName string, manifest *Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("could not encode manifest: %v", err)
+	}
+
+	if err = os.WriteFile(fileName, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("could not write manifest '%v': %v", fileName, err)
+	}
+
+	return nil
+}
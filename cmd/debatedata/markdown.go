@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// writeMarkdown writes a summary matrix as a GitHub-flavored Markdown table. The first row is the table header and is
+// followed by the separator row; columns whose cells are all numbers (or blank) are right-aligned. A non-empty legend
+// of label and issue pairs follows as a second table.
+func writeMarkdown(fileName string, data [][]string, legend [][]string) error {
+	var f = os.Stdout
+
+	if fileName != stdio {
+		var err error
+
+		if f, err = os.Create(fileName); err != nil {
+			return fmt.Errorf("could not open markdown file: %v", err)
+		}
+
+		defer func(f *os.File) {
+			err := f.Close()
+			if err != nil {
+
+			}
+		}(f)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	w := bufio.NewWriter(f)
+
+	var separator = make([]string, len(data[0]))
+
+	for colNum := range separator {
+		separator[colNum] = "---"
+
+		if numericColumn(data[1:], colNum) {
+			separator[colNum] = "--:"
+		}
+	}
+
+	writeMarkdownRow(w, data[0])
+	writeMarkdownRow(w, separator)
+
+	for _, row := range data[1:] {
+		writeMarkdownRow(w, row)
+	}
+
+	if len(legend) > 0 {
+		w.WriteString("\n")
+		writeMarkdownRow(w, []string{"Label", "Issue"})
+		writeMarkdownRow(w, []string{"---", "---"})
+
+		for _, row := range legend {
+			writeMarkdownRow(w, row)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("could not write to markdown file '%v': %v", fileName, err)
+	}
+
+	return nil
+}
+
+// writeMarkdownRow writes one table row, escaping pipes so a cell can't split into two columns
+func writeMarkdownRow(w *bufio.Writer, row []string) {
+	w.WriteString("|")
+
+	for _, cell := range row {
+		w.WriteString(" " + strings.ReplaceAll(cell, "|", `\|`) + " |")
+	}
+
+	w.WriteString("\n")
+}
+
+// numericColumn reports whether every non-blank cell in a column is a number. A column that is blank throughout is
+// not numeric.
+func numericColumn(rows [][]string, colNum int) bool {
+
+	var seen = false
+
+	for _, row := range rows {
+		if colNum >= len(row) || row[colNum] == "" {
+			continue
+		}
+
+		if _, err := strconv.ParseFloat(row[colNum], 64); err != nil {
+			return false
+		}
+
+		seen = true
+	}
+
+	return seen
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"debateData/debatedata"
+)
+
+// mergeDebates parses each dataset and concatenates the resulting debates in order. Debates from different datasets
+// stay separate entries even when they share a date, unless -dedupe-dates drops the repeats; the summary's issue
+// columns are then the union across all of them, with zero counts where a dataset never mentioned an issue.
+func mergeDebates(opts debatedata.ParseOptions, datasets ...[][]string) ([]Debate, error) {
+	return parseConcurrently(opts, len(datasets), func(k int, opts debatedata.ParseOptions) ([]Debate, error) {
+		return debatedata.ParseCSVData(datasets[k], opts)
+	})
+}
+
+// parseConcurrently runs parse for each of n inputs on a pool of runtime.NumCPU() workers and concatenates the
+// debates. The result doesn't depend on which worker finishes first: debates and warnings come out in input order,
+// and the error returned is that of the first input that failed to parse.
+func parseConcurrently(opts debatedata.ParseOptions, n int, parse func(k int, opts debatedata.ParseOptions) ([]Debate, error)) ([]Debate, error) {
+
+	type result struct {
+		debates  []Debate
+		warnings []*DataError
+		err      error
+	}
+
+	var results = make([]result, n)
+	var jobs = make(chan int)
+	var wg sync.WaitGroup
+
+	workers := runtime.NumCPU()
+
+	if workers > n {
+		workers = n
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for k := range jobs {
+				// Each worker collects its own warnings so they can be reported in input order afterwards
+				var workerOpts = opts
+
+				workerOpts.Warn = func(err *DataError) {
+					results[k].warnings = append(results[k].warnings, err)
+				}
+
+				results[k].debates, results[k].err = parse(k, workerOpts)
+			}
+		}()
+	}
+
+	for k := 0; k < n; k++ {
+		jobs <- k
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	var debates = make([]Debate, 0)
+
+	for _, r := range results {
+		if opts.Warn != nil {
+			for _, warning := range r.warnings {
+				opts.Warn(warning)
+			}
+		}
+
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		debates = append(debates, r.debates...)
+	}
+
+	return debates, nil
+}
+
+// expandGlobs replaces each input holding a glob pattern with the files it matches, in sorted order, for shells that
+// don't expand wildcards themselves. Inputs that exist as named, stdin, URLs and patterns matching nothing are kept
+// as given so they fail with the usual error when opened.
+func expandGlobs(inputs []string) []string {
+
+	var expanded []string
+
+	for _, input := range inputs {
+		if input == stdio || isURL(input) || !strings.ContainsAny(input, "*?[") {
+			expanded = append(expanded, input)
+			continue
+		}
+
+		if _, err := os.Stat(input); err == nil {
+			expanded = append(expanded, input)
+			continue
+		}
+
+		matches, err := filepath.Glob(input)
+
+		if err != nil || len(matches) == 0 {
+			expanded = append(expanded, input)
+			continue
+		}
+
+		expanded = append(expanded, matches...)
+	}
+
+	return expanded
+}
+
+// dedupeDates keeps the first debate for each calendar date (and group) and drops the later ones, which typically
+// come from input files that overlap. Dates that can't be parsed are compared as written. The dropped debates' dates
+// are returned in input order.
+func dedupeDates(debates []Debate, loc *time.Location) ([]Debate, []string) {
+
+	var kept = make([]Debate, 0, len(debates))
+	var dropped []string
+	var seen = make(map[string]bool)
+
+	for _, d := range debates {
+		day := strings.TrimSpace(d.Date)
+
+		if t, err := parseDate(d.Date, loc); err == nil {
+			day = t.Format(isoDate)
+		}
+
+		key := day + "\x00" + d.Group
+
+		if seen[key] {
+			dropped = append(dropped, d.Date)
+			continue
+		}
+
+		seen[key] = true
+		kept = append(kept, d)
+	}
+
+	return kept, dropped
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"debateData/debatedata"
+)
+
+// generatedDatasets builds files CSV datasets of rows debates each, for three candidates over three rounds
+func generatedDatasets(files int, rows int) [][][]string {
+
+	var issues = []string{"Economy", "Jobs", "Healthcare", "Education", "Climate", "Voting Rights"}
+	var header = []string{"Date"}
+
+	for _, name := range []string{"Candidate A", "Candidate B", "Candidate C"} {
+		for round := 1; round <= 3; round++ {
+			header = append(header, fmt.Sprintf("%v [%d]", name, round))
+		}
+	}
+
+	var datasets = make([][][]string, files)
+
+	for f := range datasets {
+		datasets[f] = [][]string{header}
+
+		for r := 0; r < rows; r++ {
+			var row = []string{fmt.Sprintf("%d/%d/2021", r%12+1, f%28+1)}
+
+			for c := 1; c < len(header); c++ {
+				row = append(row, issues[(f+r+c)%len(issues)]+", "+issues[(f*r+c)%len(issues)])
+			}
+
+			datasets[f] = append(datasets[f], row)
+		}
+	}
+
+	return datasets
+}
+
+// parseSerially parses each dataset in turn, the way mergeDebates did before it used a worker pool
+func parseSerially(opts debatedata.ParseOptions, datasets [][][]string) ([]Debate, error) {
+
+	var debates = make([]Debate, 0)
+
+	for _, data := range datasets {
+		parsed, err := debatedata.ParseCSVData(data, opts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		debates = append(debates, parsed...)
+	}
+
+	return debates, nil
+}
+
+// TestMergeDebatesInputOrder checks that the worker pool returns the same debates, in input order, as parsing serially
+func TestMergeDebatesInputOrder(t *testing.T) {
+
+	datasets := generatedDatasets(20, 10)
+
+	want, err := parseSerially(debatedata.ParseOptions{}, datasets)
+
+	if err != nil {
+		t.Fatalf("parseSerially: %v", err)
+	}
+
+	got, err := mergeDebates(debatedata.ParseOptions{}, datasets...)
+
+	if err != nil {
+		t.Fatalf("mergeDebates: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeDebates differs from parsing serially")
+	}
+}
+
+// BenchmarkParseSerial is the baseline for BenchmarkParseConcurrently: the same inputs parsed one after another
+func BenchmarkParseSerial(b *testing.B) {
+
+	datasets := generatedDatasets(200, 100)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		if _, err := parseSerially(debatedata.ParseOptions{}, datasets); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParseConcurrently parses many inputs on the parseConcurrently worker pool
+func BenchmarkParseConcurrently(b *testing.B) {
+
+	datasets := generatedDatasets(200, 100)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		if _, err := mergeDebates(debatedata.ParseOptions{}, datasets...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
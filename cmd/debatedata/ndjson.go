@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ndjsonRecord is one line of -format ndjson output: a single candidate's issue counts in a single debate
+type ndjsonRecord struct {
+	Date      string             `json:"date"`
+	Group     string             `json:"group,omitempty"`
+	Candidate string             `json:"candidate"`
+	Issues    map[string]float64 `json:"issues"`
+}
+
+// writeNdjson writes one JSON object per candidate per debate, one per line and without a surrounding array. Each
+// record is written as soon as it is encoded so streaming consumers can start reading before the run finishes.
+func writeNdjson(fileName string, debates *[]Debate) error {
+	var f = os.Stdout
+	var err error
+
+	if fileName != stdio {
+		if f, err = os.Create(fileName); err != nil {
+			return fmt.Errorf("could not open ndjson file: %v", err)
+		}
+
+		defer func(f *os.File) {
+			err := f.Close()
+			if err != nil {
+
+			}
+		}(f)
+	}
+
+	encoder := json.NewEncoder(f)
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			record := ndjsonRecord{
+				Date:      debate.Date,
+				Group:     debate.Group,
+				Candidate: candidate.Name,
+				Issues:    make(map[string]float64),
+			}
+
+			for issue := range candidate.IssueCount {
+				record.Issues[issue] = candidate.Score(issue)
+			}
+
+			if err = encoder.Encode(record); err != nil {
+				return fmt.Errorf("could not write to ndjson file '%v': %v", fileName, err)
+			}
+		}
+	}
+
+	return nil
+}
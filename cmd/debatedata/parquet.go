@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetRow is one row of -format parquet output: the long (tidy) shape with one row per candidate per issue per
+// debate. Date is a Parquet DATE, the number of days since 1970-01-01.
+type parquetRow struct {
+	Date      int32  `parquet:"Date,date"`
+	Candidate string `parquet:"Candidate"`
+	Issue     string `parquet:"Issue"`
+	Count     int64  `parquet:"Count"`
+}
+
+// writeParquet writes the debates to a Parquet file in the long shape. Only issues a candidate mentioned get a row.
+// Dates are typed via their calendar day in loc, so every debate date must parse.
+func writeParquet(fileName string, debates *[]Debate, loc *time.Location) error {
+	var f = os.Stdout
+	var err error
+
+	if fileName != stdio {
+		if f, err = os.Create(fileName); err != nil {
+			return fmt.Errorf("could not open parquet file: %v", err)
+		}
+
+		defer func(f *os.File) {
+			err := f.Close()
+			if err != nil {
+
+			}
+		}(f)
+	}
+
+	writer := parquet.NewWriter(f, parquet.SchemaOf(parquetRow{}))
+
+	for _, debate := range *debates {
+		t, err := parseDate(debate.Date, loc)
+
+		if err != nil {
+			return &DataError{Row: -1, Column: -1, Kind: InvalidDate, Message: err.Error()}
+		}
+
+		// Days since the epoch for the debate's calendar date, independent of the time of day and zone
+		day := int32(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+
+		for _, candidate := range debate.Candidates {
+			var issues []string
+
+			for issue := range candidate.IssueCount {
+				issues = append(issues, issue)
+			}
+
+			sort.Strings(issues)
+
+			for _, issue := range issues {
+				row := parquetRow{Date: day, Candidate: candidate.Name, Issue: issue, Count: int64(candidate.IssueCount[issue])}
+
+				if err = writer.Write(row); err != nil {
+					return fmt.Errorf("could not write to parquet file '%v': %v", fileName, err)
+				}
+			}
+		}
+	}
+
+	if err = writer.Close(); err != nil {
+		return fmt.Errorf("could not write to parquet file '%v': %v", fileName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// exitHooks run before the process exits through exit, so work like finishing a profile isn't lost on error paths
+var exitHooks []func()
+
+// exit runs the exit hooks in reverse order of registration and then exits with the given status code
+func exit(code int) {
+	for k := len(exitHooks) - 1; k >= 0; k-- {
+		exitHooks[k]()
+	}
+
+	os.Exit(code)
+}
+
+// startProfiling starts a CPU profile when cpuFile is set and arranges for a heap profile to be written to memFile
+// when it is set. The returned function finalizes both and must be called once the run is over.
+func startProfiling(cpuFile string, memFile string) (func(), error) {
+
+	var cpu *os.File
+
+	if cpuFile != "" {
+		f, err := os.Create(cpuFile)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not create cpu profile: %v", err)
+		}
+
+		if err = pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("could not start cpu profile: %v", err)
+		}
+
+		cpu = f
+	}
+
+	stop := func() {
+		if cpu != nil {
+			pprof.StopCPUProfile()
+			_ = cpu.Close()
+		}
+
+		if memFile != "" {
+			if err := writeHeapProfile(memFile); err != nil {
+				printError(err)
+			}
+		}
+	}
+
+	return stop, nil
+}
+
+// writeHeapProfile writes a heap profile reflecting all allocations made so far
+func writeHeapProfile(fileName string) error {
+	f, err := os.Create(fileName)
+
+	if err != nil {
+		return fmt.Errorf("could not create memory profile: %v", err)
+	}
+
+	defer func(f *os.File) {
+		err := f.Close()
+		if err != nil {
+
+		}
+	}(f)
+
+	// Run a collection first so the profile reflects live data rather than garbage
+	runtime.GC()
+
+	if err = pprof.WriteHeapProfile(f); err != nil {
+		return fmt.Errorf("could not write memory profile: %v", err)
+	}
+
+	return nil
+}
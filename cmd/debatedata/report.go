@@ -0,0 +1,189 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"debateData/debatedata"
+)
+
+// Report modes replace the candidate matrix with a different view of the same data
+const (
+	// reportIssues ranks the issues by their total mentions across every candidate and debate
+	reportIssues = "issues"
+	// reportDupes lists pairs of issue names so similar they are likely the same issue, for -detect-dupes
+	reportDupes = "dupes"
+	// reportTrends shows each issue's mentions per debate date, in chronological order
+	reportTrends = "trends"
+	// reportWeighted scores each candidate's issues by the -weights file's multipliers
+	reportWeighted = "weighted"
+	// reportCooccurrence counts the pairs of issues each candidate raised together in one cell
+	reportCooccurrence = "cooccurrence"
+)
+
+// summarizeIssueRanking builds a leaderboard of the issues: one row per issue with its total mentions across every
+// candidate in every debate, most-discussed first and ties in alphabetical order
+func summarizeIssueRanking(debates *[]Debate) [][]string {
+
+	var rows = [][]string{{"Issue", "TotalMentions"}}
+
+	for _, t := range rankIssues(debates) {
+		rows = append(rows, []string{t.issue, strconv.Itoa(t.total)})
+	}
+
+	return rows
+}
+
+// summarizeTrends builds an issues-by-date matrix: one row per issue and one column per debate date, in chronological
+// order, each cell holding that issue's mentions summed over the candidates in the debates on that date. Issues a
+// debate never mentioned count as 0. Restricting the candidates first (-candidate) gives a single candidate's trend.
+func summarizeTrends(debates *[]Debate, loc *time.Location) ([][]string, error) {
+
+	var sorted = append([]Debate{}, *debates...)
+
+	if err := sortDebatesByDate(&sorted, loc); err != nil {
+		return nil, err
+	}
+
+	var header = []string{"Issue"}
+	var column = make(map[string]int)
+
+	for _, d := range sorted {
+		if _, exists := column[d.Date]; !exists {
+			column[d.Date] = len(header)
+			header = append(header, d.Date)
+		}
+	}
+
+	var rows = [][]string{header}
+
+	for _, issue := range debatedata.GetIssues(&sorted) {
+		var counts = make([]int, len(header))
+
+		for _, d := range sorted {
+			for _, candidate := range d.Candidates {
+				counts[column[d.Date]] += candidate.IssueCount[issue]
+			}
+		}
+
+		var row = []string{issue}
+
+		for _, count := range counts[1:] {
+			row = append(row, strconv.Itoa(count))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// summarizeWeightedScores builds the candidate matrix with each cell holding IssueCount times the issue's weight, to
+// one decimal place, and a trailing Total column with each candidate's weighted total. The final Total row sums every
+// column. An issue weighted 0 still gets a column but adds nothing to any total.
+func summarizeWeightedScores(debates *[]Debate, weights map[string]float64, groupColumn string) [][]string {
+
+	issues := debatedata.GetIssues(debates)
+	header := append(append(debatedata.KeyColumns(groupColumn), issues...), "Total")
+	first := debatedata.FirstIssueColumn(header)
+
+	var rows = [][]string{header}
+	var columnTotals = make([]float64, len(issues)+1)
+
+	for _, d := range *debates {
+		for _, candidate := range d.Candidates {
+			var row = []string{d.Date}
+
+			if groupColumn != "" {
+				row = append(row, d.Group)
+			}
+
+			row = append(row, candidate.Name)
+
+			var total = 0.0
+
+			for k, issue := range issues {
+				score := float64(candidate.IssueCount[issue]) * issueWeight(weights, issue)
+				total += score
+				columnTotals[k] += score
+				row = append(row, strconv.FormatFloat(score, 'f', 1, 64))
+			}
+
+			columnTotals[len(issues)] += total
+			rows = append(rows, append(row, strconv.FormatFloat(total, 'f', 1, 64)))
+		}
+	}
+
+	var finalRow = make([]string, first)
+	finalRow[first-1] = "Total"
+
+	for _, total := range columnTotals {
+		finalRow = append(finalRow, strconv.FormatFloat(total, 'f', 1, 64))
+	}
+
+	return append(rows, finalRow)
+}
+
+// issuePair is an unordered pair of issues raised by one candidate in the same cell, with a before b alphabetically
+type issuePair struct {
+	candidate string
+	a         string
+	b         string
+}
+
+// summarizeCooccurrence counts, for each candidate, every unordered pair of distinct issues mentioned together in a
+// single cell across all debates, and lists the pairs as Candidate, IssueA, IssueB, PairCount rows from the most
+// frequent down. Ties are ordered by candidate and then issue names. It needs the debates parsed with
+// ParseOptions.KeepCellIssues.
+func summarizeCooccurrence(debates *[]Debate) [][]string {
+
+	var counts = make(map[issuePair]int)
+
+	for _, d := range *debates {
+		for _, candidate := range d.Candidates {
+			for _, cell := range candidate.CellIssues {
+				for i := range cell {
+					for j := i + 1; j < len(cell); j++ {
+						a, b := cell[i], cell[j]
+
+						if b < a {
+							a, b = b, a
+						}
+
+						counts[issuePair{candidate: candidate.Name, a: a, b: b}]++
+					}
+				}
+			}
+		}
+	}
+
+	var pairs []issuePair
+
+	for pair := range counts {
+		pairs = append(pairs, pair)
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		pi, pj := pairs[i], pairs[j]
+
+		switch {
+		case counts[pi] != counts[pj]:
+			return counts[pi] > counts[pj]
+		case pi.candidate != pj.candidate:
+			return pi.candidate < pj.candidate
+		case pi.a != pj.a:
+			return pi.a < pj.a
+		default:
+			return pi.b < pj.b
+		}
+	})
+
+	var rows = [][]string{{"Candidate", "IssueA", "IssueB", "PairCount"}}
+
+	for _, pair := range pairs {
+		rows = append(rows, []string{pair.candidate, pair.a, pair.b, strconv.Itoa(counts[pair])})
+	}
+
+	return rows
+}
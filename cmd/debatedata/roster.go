@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"debateData/debatedata"
+)
+
+// readRoster reads the expected candidate names, one per line. Blank lines and lines starting with the comment
+// character are skipped. Names are sanitized the same way as column titles so they match the parsed data.
+func readRoster(fileName string, comment rune) ([]string, error) {
+
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not open roster: %v", err)
+	}
+
+	defer func(f *os.File) {
+		err := f.Close()
+		if err != nil {
+
+		}
+	}(f)
+
+	var roster []string
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, string(comment)) {
+			continue
+		}
+
+		roster = append(roster, debatedata.SanitizeColumnName(line))
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read roster '%v': %v", fileName, err)
+	}
+
+	return roster, nil
+}
+
+// compareRoster returns the sorted roster names no debate has a column for, and the sorted candidate names found in
+// the debates that aren't on the roster
+func compareRoster(debates *[]Debate, roster []string) ([]string, []string) {
+
+	var expected = make(map[string]interface{})
+	var found = make(map[string]interface{})
+
+	for _, name := range roster {
+		expected[name] = nil
+	}
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			found[candidate.Name] = nil
+		}
+	}
+
+	var missing, extra []string
+
+	for name := range expected {
+		if _, exists := found[name]; !exists {
+			missing = append(missing, name)
+		}
+	}
+
+	for name := range found {
+		if _, exists := expected[name]; !exists {
+			extra = append(extra, name)
+		}
+	}
+
+	sort.Strings(missing)
+	sort.Strings(extra)
+
+	return missing, extra
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"debateData/debatedata"
+)
+
+// reconcileCounts is a self-check of ParseCSVData: it recounts the issue tokens straight from the raw cells of every
+// dataset, without any of the parsing machinery, and confirms the parsed IssueCount values add up to the same number.
+// It only holds for round-agg sum, where every token counts once.
+func reconcileCounts(debates []Debate, opts debatedata.ParseOptions, datasets ...[][]string) error {
+
+	var raw = 0
+
+	for _, data := range datasets {
+		for _, row := range data[1:] {
+			// Ragged rows only get this far when they are skipped by SkipBadRows
+			if len(row) != len(data[0]) {
+				continue
+			}
+
+			for k, cell := range row {
+				if isMetadataColumn(data[0], k, opts) {
+					continue
+				}
+
+				for _, token := range strings.Split(cell, ",") {
+					token = strings.Join(strings.Fields(token), " ")
+
+					if token == "" || (opts.DisallowIssues != nil && opts.DisallowIssues.MatchString(token)) {
+						continue
+					}
+
+					raw++
+				}
+			}
+		}
+	}
+
+	var parsed = 0
+
+	for _, debate := range debates {
+		for _, candidate := range debate.Candidates {
+			parsed += candidateTotal(candidate)
+		}
+	}
+
+	if parsed != raw {
+		return fmt.Errorf("self-check failed: parsed %d issue mentions but the raw cells hold %d", parsed, raw)
+	}
+
+	return nil
+}
+
+// isMetadataColumn reports whether header column k is the date or group column rather than candidate data
+func isMetadataColumn(header []string, k int, opts debatedata.ParseOptions) bool {
+
+	name := debatedata.SanitizeColumnName(header[k])
+
+	if opts.GroupColumn != "" && strings.EqualFold(name, opts.GroupColumn) {
+		return true
+	}
+
+	if opts.DateColIndex != nil {
+		return k == *opts.DateColIndex
+	}
+
+	if opts.DateColumn != "" {
+		return name == opts.DateColumn
+	}
+
+	return strings.Contains(name, "Date")
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// runState is what -state remembers between runs
+type runState struct {
+	// LastDate is the latest debate date processed so far; zero before the first run
+	LastDate time.Time `json:"lastDate"`
+}
+
+// lockState takes an exclusive lock on a state file by creating a ".lock" file next to it, so two runs can't read
+// and update the same state at once. The returned function removes the lock and is safe to call more than once.
+func lockState(fileName string) (func(), error) {
+
+	lockFile := fileName + ".lock"
+	f, err := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+
+	if errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("state file '%v' is in use by another run (remove '%v' if no run is active)", fileName, lockFile)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not lock state file '%v': %v", fileName, err)
+	}
+
+	fmt.Fprintln(f, os.Getpid())
+
+	if err = f.Close(); err != nil {
+		return nil, fmt.Errorf("could not lock state file '%v': %v", fileName, err)
+	}
+
+	var released = false
+
+	return func() {
+		if !released {
+			released = true
+			os.Remove(lockFile)
+		}
+	}, nil
+}
+
+// readState loads a state file. A missing file is an empty state, as on the first run.
+func readState(fileName string) (runState, error) {
+
+	var state runState
+
+	data, err := os.ReadFile(fileName)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+
+	if err != nil {
+		return state, fmt.Errorf("could not read state file '%v': %v", fileName, err)
+	}
+
+	if err = json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("could not decode state file '%v': %v", fileName, err)
+	}
+
+	return state, nil
+}
+
+// writeState saves a state file by writing a temporary file in the same directory and renaming it into place, so a
+// run that dies midway never leaves a truncated state behind
+func writeState(fileName string, state runState) error {
+
+	data, err := json.MarshalIndent(state, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("could not encode state: %v", err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".*.tmp")
+
+	if err != nil {
+		return fmt.Errorf("could not write state file '%v': %v", fileName, err)
+	}
+
+	_, err = f.Write(append(data, '\n'))
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(f.Name(), fileName)
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("could not write state file '%v': %v", fileName, err)
+	}
+
+	return nil
+}
+
+// debatesSince returns the debates dated strictly after since
+func debatesSince(debates []Debate, since time.Time, loc *time.Location) ([]Debate, error) {
+
+	var kept = make([]Debate, 0, len(debates))
+
+	for _, debate := range debates {
+		t, err := parseDate(debate.Date, loc)
+
+		if err != nil {
+			return nil, &DataError{Row: -1, Column: -1, Kind: InvalidDate, Message: err.Error()}
+		}
+
+		if t.After(since) {
+			kept = append(kept, debate)
+		}
+	}
+
+	return kept, nil
+}
+
+// latestDate returns the latest date among the debates, or the zero time when there are none
+func latestDate(debates []Debate, loc *time.Location) (time.Time, error) {
+
+	var latest time.Time
+
+	for _, debate := range debates {
+		t, err := parseDate(debate.Date, loc)
+
+		if err != nil {
+			return latest, &DataError{Row: -1, Column: -1, Kind: InvalidDate, Message: err.Error()}
+		}
+
+		if t.After(latest) {
+			latest = t
+		}
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"debateData/debatedata"
+)
+
+// datasetStats is an overview of parsed debate data, used to sanity-check a dataset before summarizing it
+type datasetStats struct {
+	debates    int
+	candidates int
+	issues     int
+	mentions   int
+	// first and last are the earliest and latest debate dates; both are zero when no date parses
+	first time.Time
+	last  time.Time
+	// badDates counts the debates whose date matches no known layout and so is left out of the range
+	badDates int
+}
+
+// collectStats counts the debates, distinct candidates and issues, and total issue mentions, and finds the range of
+// debate dates read in loc
+func collectStats(debates *[]Debate, loc *time.Location) datasetStats {
+
+	var stats = datasetStats{debates: len(*debates), issues: len(debatedata.GetIssues(debates))}
+	var candidates = make(map[string]interface{})
+
+	for _, d := range *debates {
+		for _, candidate := range d.Candidates {
+			candidates[candidate.Name] = nil
+			stats.mentions += candidateTotal(candidate)
+		}
+
+		t, err := parseDate(d.Date, loc)
+
+		if err != nil {
+			stats.badDates++
+			continue
+		}
+
+		if stats.first.IsZero() || t.Before(stats.first) {
+			stats.first = t
+		}
+
+		if stats.last.IsZero() || t.After(stats.last) {
+			stats.last = t
+		}
+	}
+
+	stats.candidates = len(candidates)
+
+	return stats
+}
+
+// printStats writes the dataset overview, one figure per line
+func printStats(w io.Writer, stats datasetStats) {
+
+	fmt.Fprintf(w, "debates:    %d\n", stats.debates)
+	fmt.Fprintf(w, "candidates: %d\n", stats.candidates)
+	fmt.Fprintf(w, "issues:     %d\n", stats.issues)
+	fmt.Fprintf(w, "mentions:   %d\n", stats.mentions)
+
+	if stats.first.IsZero() {
+		fmt.Fprintln(w, "dates:      none parsed")
+	} else {
+		fmt.Fprintf(w, "dates:      %v to %v\n", stats.first.Format(isoDate), stats.last.Format(isoDate))
+	}
+
+	if stats.badDates > 0 {
+		fmt.Fprintf(w, "unparseable dates: %d\n", stats.badDates)
+	}
+}
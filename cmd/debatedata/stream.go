@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+
+	"debateData/debatedata"
+)
+
+// csvRecords adapts a csv.Reader to debatedata.RecordReader, describing read failures the same way readCsv does
+type csvRecords struct {
+	reader *csv.Reader
+}
+
+// Read implements debatedata.RecordReader
+func (r csvRecords) Read() ([]string, error) {
+	record, err := r.reader.Read()
+
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("could not read csv: %v", err)
+	}
+
+	return record, err
+}
+
+// streamDebates parses each CSV file, or stdin for "-", as it is read instead of loading its records first, so memory
+// only grows with the parsed counts. Files are parsed concurrently and merged the same way as mergeDebates.
+func streamDebates(opts debatedata.ParseOptions, dialect csvDialect, fileNames []string) ([]Debate, error) {
+	return parseConcurrently(opts, len(fileNames), func(k int, opts debatedata.ParseOptions) ([]Debate, error) {
+		if fileNames[k] == stdio {
+			return debatedata.ParseCSV(csvRecords{dialect.newReader(os.Stdin)}, opts)
+		}
+
+		f, err := os.Open(fileNames[k])
+
+		if err != nil {
+			return nil, fmt.Errorf("could not open csv: %v", err)
+		}
+
+		defer func(f *os.File) {
+			err := f.Close()
+			if err != nil {
+
+			}
+		}(f)
+
+		return debatedata.ParseCSV(csvRecords{dialect.newReader(f)}, opts)
+	})
+}
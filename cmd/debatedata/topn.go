@@ -0,0 +1,77 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+)
+
+// topIssues returns a candidate's n most-discussed issues, sorted by count descending with ties broken
+// alphabetically. A candidate who discussed fewer than n distinct issues gets all of them.
+func topIssues(c Candidate, n int) []string {
+
+	var issues []string
+
+	for issue, count := range c.IssueCount {
+		if count > 0 {
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if c.IssueCount[issues[i]] != c.IssueCount[issues[j]] {
+			return c.IssueCount[issues[i]] > c.IssueCount[issues[j]]
+		}
+		return issues[i] < issues[j]
+	})
+
+	if len(issues) > n {
+		issues = issues[:n]
+	}
+
+	return issues
+}
+
+// summarizeTopN builds one row per candidate, in order of first appearance, listing their n most-discussed issues
+// across all debates with the count for each. Candidates with fewer than n issues get blank trailing cells.
+func summarizeTopN(debates *[]Debate, n int) [][]string {
+
+	var header = []string{"Candidate"}
+
+	for k := 1; k <= n; k++ {
+		header = append(header, "Issue "+strconv.Itoa(k), "Count "+strconv.Itoa(k))
+	}
+
+	var rows = [][]string{header}
+	var totals = make(map[string]Candidate)
+	var order []string
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			total, exists := totals[candidate.Name]
+
+			if !exists {
+				total = Candidate{Name: candidate.Name, IssueCount: make(map[string]int)}
+				totals[candidate.Name] = total
+				order = append(order, candidate.Name)
+			}
+
+			for issue, count := range candidate.IssueCount {
+				total.IssueCount[issue] += count
+			}
+		}
+	}
+
+	for _, name := range order {
+		var row = make([]string, len(header))
+		row[0] = name
+
+		for k, issue := range topIssues(totals[name], n) {
+			row[1+2*k] = issue
+			row[2+2*k] = strconv.Itoa(totals[name].IssueCount[issue])
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"debateData/debatedata"
+)
+
+// transpose pivots a summary matrix so issues run down the rows and each candidate row becomes a column. Columns are
+// labeled "Candidate (Date)", adding any group value after the date, so they stay unique across debates; rows without
+// a date, such as Total, keep just their name and so end up as trailing columns.
+func transpose(data [][]string) [][]string {
+
+	first := debatedata.FirstIssueColumn(data[0])
+
+	var header = []string{"Issue"}
+
+	for _, row := range data[1:] {
+		header = append(header, transposedLabel(row[:first]))
+	}
+
+	var rows = [][]string{header}
+
+	for colNum := first; colNum < len(data[0]); colNum++ {
+		var row = []string{data[0][colNum]}
+
+		for _, source := range data[1:] {
+			row = append(row, source[colNum])
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// transposedLabel names the column a summary row becomes: its candidate followed by the non-empty date and group
+// values in parentheses
+func transposedLabel(keys []string) string {
+
+	name := keys[len(keys)-1]
+
+	var context []string
+
+	for _, key := range keys[:len(keys)-1] {
+		if key != "" {
+			context = append(context, key)
+		}
+	}
+
+	if len(context) == 0 {
+		return name
+	}
+
+	return fmt.Sprintf("%v (%v)", name, strings.Join(context, ", "))
+}
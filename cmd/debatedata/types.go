@@ -0,0 +1,23 @@
+package main
+
+import "debateData/debatedata"
+
+// The parsed data types and their errors live in the debatedata package; these aliases keep the CLI's signatures short
+type (
+	Debate    = debatedata.Debate
+	Candidate = debatedata.Candidate
+	DataError = debatedata.DataError
+	ErrorKind = debatedata.ErrorKind
+)
+
+const (
+	DuplicateDateColumn = debatedata.DuplicateDateColumn
+	MissingDateColumn   = debatedata.MissingDateColumn
+	InvalidDate         = debatedata.InvalidDate
+	DisallowedIssue     = debatedata.DisallowedIssue
+	InvalidCount        = debatedata.InvalidCount
+	MissingGroupColumn  = debatedata.MissingGroupColumn
+	RaggedRow           = debatedata.RaggedRow
+	TotalMismatch       = debatedata.TotalMismatch
+	EmptyHeader         = debatedata.EmptyHeader
+)
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"debateData/debatedata"
+)
+
+// errorCollector gathers DataErrors during validation. Once max errors have been collected (when max > 0) further
+// errors are only counted, so huge files produce a digestible report that still conveys how bad things are.
+type errorCollector struct {
+	max     int
+	errors  []*DataError
+	dropped int
+	// warnings counts the collected and dropped errors whose severity is only a warning
+	warnings int
+}
+
+// Severities of the problems -validate reports
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// severity grades a problem: a warning leaves the data usable as it is, while an error stops a normal run or means
+// its output would be wrong
+func severity(kind ErrorKind) string {
+	switch kind {
+	case DisallowedIssue, EmptyHeader:
+		return severityWarning
+	default:
+		return severityError
+	}
+}
+
+// add records an error, or counts it as dropped once the collector is full
+func (c *errorCollector) add(err *DataError) {
+	if severity(err.Kind) == severityWarning {
+		c.warnings++
+	}
+
+	if c.max > 0 && len(c.errors) >= c.max {
+		c.dropped++
+		return
+	}
+
+	c.errors = append(c.errors, err)
+}
+
+// total returns the number of errors seen, including the dropped ones
+func (c *errorCollector) total() int {
+	return len(c.errors) + c.dropped
+}
+
+// failed reports whether any problem seen, including the dropped ones, has error severity. Warnings alone pass.
+func (c *errorCollector) failed() bool {
+	return c.total() > c.warnings
+}
+
+// validateCsvData checks raw CSV data for every problem it can find instead of stopping at the first one. Structural
+// header problems are reported first, followed by per-row problems such as ragged rows and unparseable dates. Errors
+// that are not data problems are returned.
+func validateCsvData(data [][]string, opts debatedata.ParseOptions, collector *errorCollector) error {
+
+	// Problems that only warn during a normal run are still worth reporting here, once each; ragged rows are reported
+	// and skipped so the rows after them are still checked
+	opts.Warn = collector.add
+	opts.StrictIssues = false
+	opts.SkipBadRows = true
+
+	debates, err := debatedata.ParseCSVData(data, opts)
+
+	var dataErr *DataError
+
+	if errors.As(err, &dataErr) {
+		collector.add(dataErr)
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Each debate comes from one of the rows that aren't ragged, in order, so this recovers the row it was read from
+	var rows []int
+
+	for rowNum := 1; rowNum < len(data); rowNum++ {
+		if len(data[rowNum]) == len(data[0]) {
+			rows = append(rows, rowNum)
+		}
+	}
+
+	for k, debate := range debates {
+		if _, err := parseDate(debate.Date, opts.Location); err != nil {
+			collector.add(&DataError{Row: rows[k], Column: -1, Kind: InvalidDate, Message: err.Error()})
+		}
+	}
+
+	// The summary built from the debates must add up however it is read: by issue, by candidate and overall
+	summary, err := debatedata.Summarize(&debates, debatedata.SummaryOptions{Weighted: opts.RoundWeights != nil, RowTotals: true})
+
+	if errors.As(err, &dataErr) {
+		collector.add(dataErr)
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, mismatch := range reconcileSummary(summary) {
+		collector.add(mismatch)
+	}
+
+	return nil
+}
+
+// reconcileSummary cross-checks a summary built with a Total column: each candidate row's Total must be the sum of its
+// issue cells, each cell of the final Total row the sum of its column, and the grand total in the bottom-right corner
+// both the sum of the column totals and the sum of the row totals. Every discrepancy is returned, with the row and
+// column of the offending cell.
+func reconcileSummary(summary [][]string) []*DataError {
+
+	var problems []*DataError
+
+	first := debatedata.FirstIssueColumn(summary[0])
+	last := len(summary[0]) - 1
+	totalRow := len(summary) - 1
+
+	var values = make([][]float64, len(summary))
+
+	for rowNum := 1; rowNum < len(summary); rowNum++ {
+		values[rowNum] = make([]float64, len(summary[rowNum]))
+
+		for colNum := first; colNum <= last; colNum++ {
+			val, err := strconv.ParseFloat(summary[rowNum][colNum], 64)
+
+			if err != nil {
+				problems = append(problems, &DataError{
+					Row:     rowNum,
+					Column:  colNum,
+					Kind:    InvalidCount,
+					Message: fmt.Sprintf("'%v' is not a valid summary count", summary[rowNum][colNum]),
+				})
+			}
+
+			values[rowNum][colNum] = val
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+
+	mismatch := func(rowNum int, colNum int, what string, want float64) {
+		if math.Abs(values[rowNum][colNum]-want) > 1e-9 {
+			problems = append(problems, &DataError{
+				Row:     rowNum,
+				Column:  colNum,
+				Kind:    TotalMismatch,
+				Message: fmt.Sprintf("%v is %v but its cells sum to %v", what, summary[rowNum][colNum], debatedata.FormatWeighted(want)),
+			})
+		}
+	}
+
+	var rowTotals = 0.0
+
+	for rowNum := 1; rowNum < totalRow; rowNum++ {
+		var sum = 0.0
+
+		for colNum := first; colNum < last; colNum++ {
+			sum += values[rowNum][colNum]
+		}
+
+		mismatch(rowNum, last, "the row total", sum)
+		rowTotals += values[rowNum][last]
+	}
+
+	var columnTotals = 0.0
+
+	for colNum := first; colNum < last; colNum++ {
+		var sum = 0.0
+
+		for rowNum := 1; rowNum < totalRow; rowNum++ {
+			sum += values[rowNum][colNum]
+		}
+
+		mismatch(totalRow, colNum, fmt.Sprintf("the '%v' column total", summary[0][colNum]), sum)
+		columnTotals += values[totalRow][colNum]
+	}
+
+	mismatch(totalRow, last, "the grand total (by issue)", columnTotals)
+	mismatch(totalRow, last, "the grand total (by candidate)", rowTotals)
+
+	return problems
+}
+
+// printValidation writes the collected errors, one per line with their severity, followed by a count of any that were
+// not shown and a tally by severity
+func printValidation(w io.Writer, fileName string, collector *errorCollector) {
+
+	if collector.total() == 0 {
+		fmt.Fprintf(w, "%v: no problems found\n", fileName)
+		return
+	}
+
+	for _, err := range collector.errors {
+		fmt.Fprintf(w, "%v: %v: %v\n", fileName, severity(err.Kind), err)
+	}
+
+	if collector.dropped > 0 {
+		fmt.Fprintf(w, "... and %d more\n", collector.dropped)
+	}
+
+	fmt.Fprintf(w, "%v: %d errors, %d warnings\n", fileName, collector.total()-collector.warnings, collector.warnings)
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"debateData/debatedata"
+)
+
+// xlsxSheet is one worksheet of an -format xlsx workbook
+type xlsxSheet struct {
+	name string
+	rows [][]string
+}
+
+// xlsxPart is one file inside the workbook's zip package
+type xlsxPart struct {
+	name string
+	data string
+}
+
+// xlsxMaxSheetName is the longest sheet name Excel accepts
+const xlsxMaxSheetName = 31
+
+// xlsxColumnWidth bounds the auto-sized column widths, in characters
+const (
+	xlsxMinColumnWidth = 8
+	xlsxMaxColumnWidth = 60
+)
+
+// debateSheets returns a sheet per debate holding that debate's own summary, named after its date (and group)
+func debateSheets(debates *[]Debate, opts debatedata.SummaryOptions) ([]xlsxSheet, error) {
+
+	var sheets []xlsxSheet
+
+	for _, d := range *debates {
+		rows, err := debatedata.Summarize(&[]Debate{d}, opts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		name := d.Date
+
+		if d.Group != "" {
+			name = d.Date + " " + d.Group
+		}
+
+		sheets = append(sheets, xlsxSheet{name: name, rows: rows})
+	}
+
+	return sheets, nil
+}
+
+// sheetName makes a name Excel accepts: the characters []:*?/\ become '-', it is cut to 31 characters, and a numbered
+// suffix keeps it distinct from the names already used (compared case-insensitively, as Excel does)
+func sheetName(name string, used map[string]bool) string {
+
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+
+	if name == "" {
+		name = "Sheet"
+	}
+
+	var candidate = truncateRunes(name, xlsxMaxSheetName)
+
+	for n := 2; used[strings.ToLower(candidate)]; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		candidate = truncateRunes(name, xlsxMaxSheetName-len(suffix)) + suffix
+	}
+
+	used[strings.ToLower(candidate)] = true
+
+	return candidate
+}
+
+// truncateRunes cuts s to at most n characters
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	return string([]rune(s)[:n])
+}
+
+// columnName returns the spreadsheet letters of a zero-based column index: A, B, ..., Z, AA, ...
+func columnName(col int) string {
+
+	var name []byte
+
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = append([]byte{byte('A' + (col-1)%26)}, name...)
+	}
+
+	return string(name)
+}
+
+// writeXlsx writes the sheets as an Office Open XML workbook. Each sheet's header row is frozen and its columns are
+// sized to their longest cell; cells that hold numbers are stored as numbers so they can be summed in Excel.
+func writeXlsx(fileName string, sheets []xlsxSheet) error {
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	var used = make(map[string]bool)
+	var workbookSheets, workbookRels, contentTypes strings.Builder
+
+	for k, sheet := range sheets {
+		fmt.Fprintf(&workbookSheets, `<sheet name="%v" sheetId="%d" r:id="rId%d"/>`, xmlEscape(sheetName(sheet.name, used)), k+1, k+1)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, k+1, k+1)
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, k+1)
+	}
+
+	var parts = []xlsxPart{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			contentTypes.String() + `</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + workbookSheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			workbookRels.String() + `</Relationships>`},
+	}
+
+	for k, sheet := range sheets {
+		parts = append(parts, xlsxPart{fmt.Sprintf("xl/worksheets/sheet%d.xml", k+1), worksheetXml(sheet.rows)})
+	}
+
+	for _, part := range parts {
+		w, err := zw.Create(part.name)
+
+		if err == nil {
+			_, err = w.Write([]byte(part.data))
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not encode xlsx: %v", err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("could not encode xlsx: %v", err)
+	}
+
+	if err := writeOutputFile(fileName, buf.Bytes()); err != nil {
+		return fmt.Errorf("could not write to xlsx file '%v': %v", fileName, err)
+	}
+
+	return nil
+}
+
+// worksheetXml renders the rows of one sheet, with the first row frozen as its header. Cells of the columns that
+// markdown output right-aligns are stored as numbers.
+func worksheetXml(rows [][]string) string {
+
+	var widths []int
+	var numeric []bool
+
+	for _, row := range rows {
+		for col, cell := range row {
+			for len(widths) <= col {
+				widths = append(widths, xlsxMinColumnWidth)
+			}
+
+			if n := utf8.RuneCountInString(cell) + 2; n > widths[col] {
+				widths[col] = n
+			}
+		}
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	sb.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+
+	if len(widths) > 0 {
+		sb.WriteString(`<cols>`)
+
+		for col, width := range widths {
+			fmt.Fprintf(&sb, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, col+1, col+1, min(width, xlsxMaxColumnWidth))
+		}
+
+		sb.WriteString(`</cols>`)
+	}
+
+	for col := range widths {
+		numeric = append(numeric, len(rows) > 1 && numericColumn(rows[1:], col))
+	}
+
+	sb.WriteString(`<sheetData>`)
+
+	for rowNum, row := range rows {
+		fmt.Fprintf(&sb, `<row r="%d">`, rowNum+1)
+
+		for col, cell := range row {
+			ref := columnName(col) + strconv.Itoa(rowNum+1)
+
+			// The header row stays text so issue names such as "2020" aren't turned into numbers
+			if rowNum > 0 && numeric[col] && cell != "" {
+				fmt.Fprintf(&sb, `<c r="%v"><v>%v</v></c>`, ref, cell)
+			} else if cell != "" {
+				fmt.Fprintf(&sb, `<c r="%v" t="inlineStr"><is><t xml:space="preserve">%v</t></is></c>`, ref, xmlEscape(cell))
+			}
+		}
+
+		sb.WriteString(`</row>`)
+	}
+
+	sb.WriteString(`</sheetData></worksheet>`)
+
+	return sb.String()
+}
+
+// xmlEscape escapes text for use in XML content and attribute values
+func xmlEscape(s string) string {
+
+	var buf bytes.Buffer
+
+	if err := xml.EscapeText(&buf, []byte(s)); err != nil {
+		return s
+	}
+
+	return buf.String()
+}
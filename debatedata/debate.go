@@ -0,0 +1,537 @@
+// Package debatedata parses debate issue-tracking CSV data and summarizes how often each candidate discussed each issue
+package debatedata
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Debate holds the issue counts of every candidate in a single debate
+type Debate struct {
+	Date string `json:"date"`
+	// Group holds the value of the ParseOptions.GroupColumn metadata column for this debate, if one was designated
+	Group      string      `json:"group,omitempty"`
+	Candidates []Candidate `json:"candidates"`
+}
+
+// Candidate holds how often one candidate discussed each issue during a debate
+type Candidate struct {
+	Name       string         `json:"name"`
+	IssueCount map[string]int `json:"issueCount"`
+	// WeightedCount holds the round-weighted issue counts. It is only populated when round weights are in use.
+	WeightedCount map[string]float64 `json:"weightedCount,omitempty"`
+	// CellIssues holds the distinct issues of each non-empty cell, in first-mention order. It is only populated when
+	// ParseOptions.KeepCellIssues is set.
+	CellIssues [][]string `json:"cellIssues,omitempty"`
+	// RoundCounts holds the issue counts of each round, keyed by the round number of the column title's [#] suffix (0
+	// for a title without one). It is only populated when ParseOptions.KeepRounds is set.
+	RoundCounts map[int]map[string]int `json:"roundCounts,omitempty"`
+}
+
+// Score returns the candidate's weighted count for an issue when round weights are in use, otherwise the raw count
+func (c Candidate) Score(issue string) float64 {
+	if c.WeightedCount != nil {
+		return c.WeightedCount[issue]
+	}
+
+	return float64(c.IssueCount[issue])
+}
+
+// Round aggregation modes control how a candidate's per-round ([1], [2], ...) columns combine into one IssueCount
+const (
+	// RoundAggSum adds the counts from every round
+	RoundAggSum = "sum"
+	// RoundAggMax keeps the largest count from any single round
+	RoundAggMax = "max"
+	// RoundAggDistinct records 1 for any issue mentioned in at least one round
+	RoundAggDistinct = "distinct"
+)
+
+// Count modes control how repeated issue tokens within a single candidate cell are counted
+const (
+	// CountMentions counts every token, so "Economy, Economy" in one cell counts twice
+	CountMentions = "mentions"
+	// CountRounds counts each issue at most once per cell, giving the number of rounds that raised it
+	CountRounds = "rounds"
+)
+
+// ParseOptions controls how ParseCSVData interprets the raw CSV data
+type ParseOptions struct {
+	// RoundAgg is RoundAggSum, RoundAggMax or RoundAggDistinct. Any other value, including empty, sums the rounds.
+	RoundAgg string
+	// CountMode is CountMentions or CountRounds. Any other value, including empty, counts mentions.
+	CountMode string
+	// RoundWeights multiplies each round's contribution by its weight. Rounds without a weight count once; nil
+	// disables weighting.
+	RoundWeights map[int]float64
+	// FoldAccents merges names that differ only in accents or Unicode normalization, keeping the first-seen form
+	FoldAccents bool
+	// Aliases maps lowercased issue variants to their canonical names, keyed by AliasKey. nil disables it.
+	Aliases map[string]string
+	// CandidateAliases maps candidate header variants, keyed by AliasKey, to the canonical name their columns are
+	// merged under. nil disables it.
+	CandidateAliases map[string]string
+	// AliasApplied is called for each header column renamed by CandidateAliases. nil discards it.
+	AliasApplied func(variant, canonical string)
+	// IgnoreIssueCase merges issue names that differ only in case, displaying the form chosen by CaseCanonical
+	IgnoreIssueCase bool
+	// CaseCanonical is CaseCanonicalFirst or CaseCanonicalMostCommon. Any other value, including empty, keeps the first.
+	CaseCanonical string
+	// Location is the time zone used to interpret debate dates
+	Location *time.Location
+	// DisallowIssues matches issue tokens that are dropped rather than counted, such as stray numbers. nil allows
+	// every token.
+	DisallowIssues *regexp.Regexp
+	// StrictIssues makes any disallowed issue token a parse error instead of a warning
+	StrictIssues bool
+	// KeepCellIssues records each cell's issues in Candidate.CellIssues, for analyses of issues raised together
+	KeepCellIssues bool
+	// KeepRounds records each round's issue counts in Candidate.RoundCounts alongside the aggregated IssueCount
+	KeepRounds bool
+	// SkipBadRows leaves out rows whose column count differs from the header's with a warning, instead of failing
+	SkipBadRows bool
+	// Warn receives problems that don't stop parsing. nil discards them.
+	Warn func(*DataError)
+	// DateColIndex designates the date column by its zero-based position, bypassing the name match. nil keeps the
+	// name match.
+	DateColIndex *int
+	// DateColumn designates the date column by its exact (sanitized) header name instead of the default match on any
+	// header containing "Date". Empty disables it.
+	DateColumn string
+	// GroupColumn names a metadata column, matched case-insensitively, whose value is kept as each debate's Group
+	// instead of being read as a candidate. Empty disables it.
+	GroupColumn string
+}
+
+// RecordReader yields CSV records one at a time and io.EOF once there are no more. *csv.Reader satisfies it.
+type RecordReader interface {
+	Read() ([]string, error)
+}
+
+// recordSlice is a RecordReader over records that are already in memory
+type recordSlice struct {
+	records [][]string
+}
+
+// Read implements RecordReader
+func (r *recordSlice) Read() ([]string, error) {
+	if len(r.records) == 0 {
+		return nil, io.EOF
+	}
+
+	record := r.records[0]
+	r.records = r.records[1:]
+
+	return record, nil
+}
+
+// ParseCSVData takes CSV data and converts it to a native data structure
+func ParseCSVData(data [][]string, opts ParseOptions) ([]Debate, error) {
+	return ParseCSV(&recordSlice{records: data}, opts)
+}
+
+// ParseCSV is ParseCSVData for records read one at a time, so a large file never has to be held in memory as a
+// whole: only the parsed counts accumulate. Errors from the reader are returned as they are.
+func ParseCSV(r RecordReader, opts ParseOptions) ([]Debate, error) {
+
+	var debates = make([]Debate, 0)
+
+	header, err := r.Read()
+
+	if err == io.EOF {
+		return nil, fmt.Errorf("the source data has no header row")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a map of the column indices for each Candidate. This is necessary because each Candidate has data
+	// across multiple columns with different naming patterns for each debate round ([1], [2], [3], etc)
+	indexMap := make(map[string][]int)
+
+	// Keep the order in which each sanitized column first appears so candidates come out in input order
+	var columnOrder []string
+
+	// The date column is found by position when a date column index is given, otherwise by name
+	var dateIndex = -1
+	var groupIndex = -1
+
+	if index := opts.DateColIndex; index != nil && (*index < 0 || *index >= len(header)) {
+		return nil, &DataError{
+			Row:     0,
+			Column:  *index,
+			Kind:    MissingDateColumn,
+			Message: fmt.Sprintf("date column index %d is outside the %d header columns", *index, len(header)),
+		}
+	}
+
+	// Display forms for accent-folded candidate and issue names, shared across every debate
+	candidateNames := make(displayNames)
+	issueNames := make(displayNames)
+	issueCase := newIssueCasing(opts.CaseCanonical)
+
+	for k, v := range header {
+		sanitizedValue := SanitizeColumnName(v)
+
+		if opts.FoldAccents {
+			sanitizedValue = candidateNames.canonical(sanitizedValue)
+		}
+
+		atDateIndex := opts.DateColIndex != nil && k == *opts.DateColIndex
+
+		if opts.GroupColumn != "" && strings.EqualFold(sanitizedValue, opts.GroupColumn) && !atDateIndex {
+			groupIndex = k
+			continue
+		}
+
+		// Without a designated name any header containing "Date" is the date column
+		isDate := strings.Contains(sanitizedValue, "Date")
+
+		if opts.DateColumn != "" {
+			isDate = sanitizedValue == opts.DateColumn
+		}
+
+		if opts.DateColIndex != nil {
+			if atDateIndex {
+				dateIndex = k
+				continue
+			}
+		} else if isDate {
+			// We are only expecting one date column
+			if dateIndex >= 0 {
+				return nil, &DataError{
+					Row:     0,
+					Column:  k,
+					Kind:    DuplicateDateColumn,
+					Message: "the source data contains more than one date column",
+				}
+			}
+			dateIndex = k
+			continue
+		}
+
+		if sanitizedValue == "" && opts.Warn != nil {
+			opts.Warn(&DataError{
+				Row:     0,
+				Column:  k,
+				Kind:    EmptyHeader,
+				Message: "the candidate column has no name",
+			})
+		}
+
+		// A candidate listed under several names ("Sanders", "B. Sanders") gets its columns merged like rounds
+		if canonical, exists := opts.CandidateAliases[AliasKey(sanitizedValue)]; exists && canonical != sanitizedValue {
+			if opts.AliasApplied != nil {
+				opts.AliasApplied(sanitizedValue, canonical)
+			}
+
+			sanitizedValue = canonical
+		}
+
+		if _, exists := indexMap[sanitizedValue]; !exists {
+			columnOrder = append(columnOrder, sanitizedValue)
+		}
+
+		indexMap[sanitizedValue] = append(indexMap[sanitizedValue], k)
+	}
+
+	if dateIndex < 0 && opts.DateColumn != "" {
+		return nil, &DataError{
+			Row:     0,
+			Column:  -1,
+			Kind:    MissingDateColumn,
+			Message: fmt.Sprintf("the source data has no '%v' date column", opts.DateColumn),
+		}
+	}
+
+	if dateIndex < 0 {
+		return nil, &DataError{
+			Row:     0,
+			Column:  -1,
+			Kind:    MissingDateColumn,
+			Message: "no date column found in header",
+		}
+	}
+
+	if opts.GroupColumn != "" && groupIndex < 0 {
+		return nil, &DataError{
+			Row:     0,
+			Column:  -1,
+			Kind:    MissingGroupColumn,
+			Message: fmt.Sprintf("the source data has no '%v' column", opts.GroupColumn),
+		}
+	}
+
+	// The first disallowed issue token found, returned as the error in strict mode
+	var disallowed *DataError
+	var disallowedCount = 0
+
+	// Read the rows following the header one at a time
+	for rowOffset := 0; ; rowOffset++ {
+		debateData, err := r.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// A row truncated (or padded) during export can't be lined up with the header's columns
+		if len(debateData) != len(header) {
+			dataErr := &DataError{
+				Row:     rowOffset + 1,
+				Column:  -1,
+				Kind:    RaggedRow,
+				Message: fmt.Sprintf("the row has %d columns but the header has %d", len(debateData), len(header)),
+			}
+
+			if !opts.SkipBadRows {
+				return nil, dataErr
+			}
+
+			if opts.Warn != nil {
+				opts.Warn(dataErr)
+			}
+
+			continue
+		}
+
+		// Create an instance of Debate to store data about the debate
+		var debate Debate
+
+		debate.Date = debateData[dateIndex]
+
+		if groupIndex >= 0 {
+			debate.Group = debateData[groupIndex]
+		}
+
+		// Iterate the columns in input order; everything other than the date and group columns is Candidate data
+		for _, rowKey := range columnOrder {
+			index := indexMap[rowKey]
+
+			var candidate Candidate
+			candidate.Name = rowKey
+			candidate.IssueCount = make(map[string]int)
+
+			if opts.RoundWeights != nil {
+				candidate.WeightedCount = make(map[string]float64)
+			}
+
+			if opts.KeepRounds {
+				candidate.RoundCounts = make(map[int]map[string]int)
+			}
+
+			for _, indexVal := range index {
+
+				// Count the issues in this round's cell on their own so they can be combined with the other
+				// rounds according to the round aggregation mode
+				roundCount := make(map[string]int)
+				var cellIssues []string
+
+				// Here we take data from each Candidate cell, split it by the comma, trim any whitespace and
+				// collapse inner runs of it to get a clean issue name, so "Health  care " counts as "Health care"
+				issues := strings.Split(debateData[indexVal], ",")
+
+				for _, issue := range issues {
+					issue = strings.Join(strings.Fields(issue), " ")
+
+					// handle empty cells
+					if issue == "" {
+						continue
+					}
+
+					// Tokens such as stray numbers or "N/A" aren't issues; report them and leave them out
+					if opts.DisallowIssues != nil && opts.DisallowIssues.MatchString(issue) {
+						dataErr := &DataError{
+							Row:     rowOffset + 1,
+							Column:  indexVal,
+							Kind:    DisallowedIssue,
+							Message: fmt.Sprintf("'%v' is not an allowed issue name", issue),
+						}
+
+						if disallowed == nil {
+							disallowed = dataErr
+						}
+
+						disallowedCount++
+
+						if opts.Warn != nil {
+							opts.Warn(dataErr)
+						}
+
+						continue
+					}
+
+					if canonical, exists := opts.Aliases[AliasKey(issue)]; exists {
+						issue = canonical
+					}
+
+					if opts.FoldAccents {
+						issue = issueNames.canonical(issue)
+					}
+
+					if opts.IgnoreIssueCase {
+						issue = issueCase.key(issue)
+					}
+
+					if _, seen := roundCount[issue]; !seen {
+						cellIssues = append(cellIssues, issue)
+					}
+
+					if opts.CountMode == CountRounds {
+						roundCount[issue] = 1
+					} else {
+						roundCount[issue]++
+					}
+				}
+
+				if opts.KeepCellIssues && len(cellIssues) > 0 {
+					candidate.CellIssues = append(candidate.CellIssues, cellIssues)
+				}
+
+				// Columns that share a round number, such as aliases of one candidate, add up within the round
+				if opts.KeepRounds && len(roundCount) > 0 {
+					round := roundNumber(header[indexVal])
+
+					if candidate.RoundCounts[round] == nil {
+						candidate.RoundCounts[round] = make(map[string]int)
+					}
+
+					for issue, count := range roundCount {
+						candidate.RoundCounts[round][issue] += count
+					}
+				}
+
+				for issue, count := range roundCount {
+					switch opts.RoundAgg {
+					case RoundAggMax:
+						if count > candidate.IssueCount[issue] {
+							candidate.IssueCount[issue] = count
+						}
+					case RoundAggDistinct:
+						candidate.IssueCount[issue] = 1
+					default:
+						candidate.IssueCount[issue] += count
+					}
+
+					if candidate.WeightedCount == nil {
+						continue
+					}
+
+					// Weighted counts combine the same way, scaling each round by its weight. Under distinct
+					// aggregation an issue is worth the weight of the heaviest round that mentioned it.
+					weight, exists := opts.RoundWeights[roundNumber(header[indexVal])]
+
+					if !exists {
+						weight = 1
+					}
+
+					switch opts.RoundAgg {
+					case RoundAggMax:
+						candidate.WeightedCount[issue] = math.Max(candidate.WeightedCount[issue], float64(count)*weight)
+					case RoundAggDistinct:
+						candidate.WeightedCount[issue] = math.Max(candidate.WeightedCount[issue], weight)
+					default:
+						candidate.WeightedCount[issue] += float64(count) * weight
+					}
+				}
+
+			}
+
+			// Add the candidate to the debate
+			debate.Candidates = append(debate.Candidates, candidate)
+
+		}
+
+		// Add the debate to the debates slice
+		debates = append(debates, debate)
+
+	}
+
+	if opts.StrictIssues && disallowed != nil {
+		return nil, &DataError{
+			Row:     disallowed.Row,
+			Column:  disallowed.Column,
+			Kind:    DisallowedIssue,
+			Message: fmt.Sprintf("%v (%d disallowed issue tokens in total)", disallowed.Message, disallowedCount),
+		}
+	}
+
+	if opts.IgnoreIssueCase {
+		issueCase.apply(debates)
+	}
+
+	// return the conditioned data
+	return debates, nil
+}
+
+// roundSuffix matches the round number of a column title, e.g. the "[12]" of "Candidate A [12]"
+var roundSuffix = regexp.MustCompile(`\[(\d+)\]`)
+
+// roundNumber returns the round number from a column title's [#] suffix, or 0 when the title has none
+func roundNumber(val string) int {
+
+	match := roundSuffix.FindStringSubmatch(val)
+
+	if match == nil {
+		return 0
+	}
+
+	round, _ := strconv.Atoi(match[1])
+
+	return round
+}
+
+// SanitizeColumnName remove the round number ([1], [12], ...) from column title, and collapse runs of whitespace to a
+// single space so "John  Doe [1]" and "John Doe [12]" name the same candidate.
+func SanitizeColumnName(val string) string {
+
+	candName := strings.Join(strings.Fields(roundSuffix.ReplaceAllString(val, "")), " ")
+
+	return candName
+
+}
+
+// GetIssues returns a deduplicated list of the issues discussed during the debates, sorted case-insensitively in
+// ascending order with ties broken by the original casing, so the order never depends on map iteration
+func GetIssues(debates *[]Debate) []string {
+
+	var issues = make(map[string]interface{})
+
+	// Iterate all debates and candidates to get a unique list of Issues
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			for issue := range candidate.IssueCount {
+				issues[issue] = nil
+			}
+		}
+	}
+
+	var issueSlice []string
+
+	// Convert the map to a slice and return it
+	for k, _ := range issues {
+		issueSlice = append(issueSlice, k)
+	}
+
+	sort.Slice(issueSlice, func(i, j int) bool {
+		li, lj := strings.ToLower(issueSlice[i]), strings.ToLower(issueSlice[j])
+
+		if li != lj {
+			return li < lj
+		}
+
+		return issueSlice[i] < issueSlice[j]
+	})
+
+	return issueSlice
+}
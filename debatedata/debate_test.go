@@ -0,0 +1,475 @@
+package debatedata
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// readFixture reads an in-memory CSV fixture into records
+func readFixture(t *testing.T, text string) [][]string {
+	t.Helper()
+
+	records, err := csv.NewReader(strings.NewReader(text)).ReadAll()
+
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	return records
+}
+
+// TestGetIssuesStableOrder checks that parsing and summarizing the same data 100 times gives the same issue order and
+// the same summary every time, case-insensitive with ties broken by the original casing, however the maps iterate
+func TestGetIssuesStableOrder(t *testing.T) {
+
+	data := [][]string{
+		{"Date", "Candidate A [1]", "Candidate B [1]"},
+		{"1/1/2021", "Jobs, economy, Voting Rights", "Economy, abortion, Healthcare"},
+		{"2/1/2021", "Democracy, Zoning", "jobs, Education, Climate"},
+	}
+
+	want := []string{"abortion", "Climate", "Democracy", "Economy", "economy", "Education", "Healthcare", "Jobs", "jobs", "Voting Rights", "Zoning"}
+
+	var first [][]string
+
+	for run := 0; run < 100; run++ {
+		debates, err := ParseCSVData(data, ParseOptions{})
+
+		if err != nil {
+			t.Fatalf("run %d: ParseCSVData: %v", run, err)
+		}
+
+		if got := GetIssues(&debates); !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d: GetIssues = %q, want %q", run, got, want)
+		}
+
+		summary, err := Summarize(&debates, SummaryOptions{})
+
+		if err != nil {
+			t.Fatalf("run %d: Summarize: %v", run, err)
+		}
+
+		if run == 0 {
+			first = summary
+		} else if !reflect.DeepEqual(summary, first) {
+			t.Fatalf("run %d: Summarize = %q, want %q as on the first run", run, summary, first)
+		}
+	}
+}
+
+// TestSanitizeColumnNameRoundNumbers checks that round suffixes of any length are removed along with the spaces they
+// leave behind
+func TestSanitizeColumnNameRoundNumbers(t *testing.T) {
+
+	tests := []struct {
+		column string
+		want   string
+	}{
+		{"Smith [1]", "Smith"},
+		{"Smith [12]", "Smith"},
+		{"Smith [100]", "Smith"},
+		{"John  Doe [10]", "John Doe"},
+		{" Smith [3] ", "Smith"},
+		{"Smith", "Smith"},
+	}
+
+	for _, test := range tests {
+		if got := SanitizeColumnName(test.column); got != test.want {
+			t.Errorf("SanitizeColumnName(%q) = %q, want %q", test.column, got, test.want)
+		}
+	}
+}
+
+// TestParseCSVDataMultiDigitRounds checks that round columns numbered [1], [12] and [100] belong to one candidate
+func TestParseCSVDataMultiDigitRounds(t *testing.T) {
+
+	data := [][]string{
+		{"Date", "Smith [1]", "Smith [12]", "Smith [100]"},
+		{"1/1/2021", "Economy", "Economy, Jobs", "Jobs"},
+	}
+
+	debates, err := ParseCSVData(data, ParseOptions{})
+
+	if err != nil {
+		t.Fatalf("ParseCSVData: %v", err)
+	}
+
+	if len(debates) != 1 || len(debates[0].Candidates) != 1 {
+		t.Fatalf("ParseCSVData = %+v, want one debate with one candidate", debates)
+	}
+
+	candidate := debates[0].Candidates[0]
+
+	if candidate.Name != "Smith" {
+		t.Errorf("candidate name = %q, want %q", candidate.Name, "Smith")
+	}
+
+	if want := map[string]int{"Economy": 2, "Jobs": 2}; !reflect.DeepEqual(candidate.IssueCount, want) {
+		t.Errorf("IssueCount = %v, want %v", candidate.IssueCount, want)
+	}
+}
+
+// TestParseCSVDataDateColumn checks that the header must hold exactly one date column
+func TestParseCSVDataDateColumn(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		header []string
+		// kind is the expected DataError kind, or -1 when parsing should succeed
+		kind ErrorKind
+	}{
+		{"none", []string{"When", "Candidate A [1]"}, MissingDateColumn},
+		{"one", []string{"Debate Date", "Candidate A [1]"}, -1},
+		{"two", []string{"Date", "Candidate A [1]", "Date [2]"}, DuplicateDateColumn},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			row := make([]string, len(test.header))
+			row[0], row[1] = "1/1/2021", "Economy"
+
+			debates, err := ParseCSVData([][]string{test.header, row}, ParseOptions{})
+
+			if test.kind < 0 {
+				if err != nil {
+					t.Fatalf("ParseCSVData: %v", err)
+				}
+
+				if len(debates) != 1 || debates[0].Date != "1/1/2021" {
+					t.Errorf("ParseCSVData = %+v, want one debate dated 1/1/2021", debates)
+				}
+
+				return
+			}
+
+			var dataErr *DataError
+
+			if !errors.As(err, &dataErr) || dataErr.Kind != test.kind {
+				t.Fatalf("ParseCSVData error = %v, want a %v DataError", err, test.kind)
+			}
+		})
+	}
+}
+
+// TestParseCSVDataCounts checks how cells turn into each candidate's issue counts
+func TestParseCSVDataCounts(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		fixture string
+		// want holds each debate's date and each candidate's counts, in order
+		want []Debate
+	}{
+		{
+			name: "rounds collapse into one candidate",
+			fixture: "Date,Candidate A [1],Candidate A [2],Candidate A [3]\n" +
+				"1/1/2021,Economy,\"Economy, Jobs\",Healthcare\n",
+			want: []Debate{{Date: "1/1/2021", Candidates: []Candidate{
+				{Name: "Candidate A", IssueCount: map[string]int{"Economy": 2, "Jobs": 1, "Healthcare": 1}},
+			}}},
+		},
+		{
+			name: "empty cells count nothing",
+			fixture: "Date,Candidate A [1],Candidate B [1]\n" +
+				"1/1/2021,,Jobs\n" +
+				"2/1/2021,  ,\n",
+			want: []Debate{
+				{Date: "1/1/2021", Candidates: []Candidate{
+					{Name: "Candidate A", IssueCount: map[string]int{}},
+					{Name: "Candidate B", IssueCount: map[string]int{"Jobs": 1}},
+				}},
+				{Date: "2/1/2021", Candidates: []Candidate{
+					{Name: "Candidate A", IssueCount: map[string]int{}},
+					{Name: "Candidate B", IssueCount: map[string]int{}},
+				}},
+			},
+		},
+		{
+			name: "duplicate issues within a cell each count",
+			fixture: "Date,Candidate A [1]\n" +
+				"1/1/2021,\"Economy, Economy,Jobs , Economy\"\n",
+			want: []Debate{{Date: "1/1/2021", Candidates: []Candidate{
+				{Name: "Candidate A", IssueCount: map[string]int{"Economy": 3, "Jobs": 1}},
+			}}},
+		},
+		{
+			name: "date column found by name anywhere in the header",
+			fixture: "Candidate A [1],Debate Date,Candidate B [1]\n" +
+				"Economy,3/1/2021,Jobs\n",
+			want: []Debate{{Date: "3/1/2021", Candidates: []Candidate{
+				{Name: "Candidate A", IssueCount: map[string]int{"Economy": 1}},
+				{Name: "Candidate B", IssueCount: map[string]int{"Jobs": 1}},
+			}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			debates, err := ParseCSVData(readFixture(t, test.fixture), ParseOptions{})
+
+			if err != nil {
+				t.Fatalf("ParseCSVData: %v", err)
+			}
+
+			if !reflect.DeepEqual(debates, test.want) {
+				t.Errorf("ParseCSVData = %+v, want %+v", debates, test.want)
+			}
+		})
+	}
+}
+
+// TestParseCSVMatchesParseCSVData checks that reading records one at a time parses the same as handing over all of
+// them at once
+func TestParseCSVMatchesParseCSVData(t *testing.T) {
+
+	text := "Date,Candidate A [1],Candidate A [2],Candidate B [1]\n" +
+		"1/1/2021,\"Economy, Jobs\",Healthcare,Education\n" +
+		"2/1/2021,Jobs,,\"Economy, Climate\"\n"
+
+	want, err := ParseCSVData(readFixture(t, text), ParseOptions{})
+
+	if err != nil {
+		t.Fatalf("ParseCSVData: %v", err)
+	}
+
+	got, err := ParseCSV(csv.NewReader(strings.NewReader(text)), ParseOptions{})
+
+	if err != nil {
+		t.Fatalf("ParseCSV: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseCSV = %+v, want %+v", got, want)
+	}
+}
+
+// failingReader returns its header and then err
+type failingReader struct {
+	header []string
+	err    error
+}
+
+// Read implements RecordReader
+func (r *failingReader) Read() ([]string, error) {
+	if r.header != nil {
+		header := r.header
+		r.header = nil
+		return header, nil
+	}
+
+	return nil, r.err
+}
+
+// TestParseCSVReaderErrors checks that an empty input is an error and that reader errors come back unchanged
+func TestParseCSVReaderErrors(t *testing.T) {
+
+	if _, err := ParseCSV(&failingReader{err: io.EOF}, ParseOptions{}); err == nil {
+		t.Errorf("ParseCSV of an empty input succeeded")
+	}
+
+	readErr := errors.New("disk on fire")
+
+	if _, err := ParseCSV(&failingReader{header: []string{"Date", "Candidate A [1]"}, err: readErr}, ParseOptions{}); err != readErr {
+		t.Errorf("ParseCSV error = %v, want %v", err, readErr)
+	}
+}
+
+// TestParseCSVDataRaggedRow checks that a row with the wrong number of columns fails unless SkipBadRows is set, which
+// drops it with a warning
+func TestParseCSVDataRaggedRow(t *testing.T) {
+
+	data := [][]string{
+		{"Date", "Candidate A [1]", "Candidate B [1]"},
+		{"1/1/2021", "Economy"},
+		{"2/1/2021", "Jobs", "Healthcare"},
+	}
+
+	var dataErr *DataError
+
+	if _, err := ParseCSVData(data, ParseOptions{}); !errors.As(err, &dataErr) || dataErr.Kind != RaggedRow || dataErr.Row != 1 {
+		t.Fatalf("ParseCSVData error = %v, want a ragged row DataError at row 1", err)
+	}
+
+	var warnings []*DataError
+
+	debates, err := ParseCSVData(data, ParseOptions{SkipBadRows: true, Warn: func(err *DataError) { warnings = append(warnings, err) }})
+
+	if err != nil {
+		t.Fatalf("ParseCSVData with SkipBadRows: %v", err)
+	}
+
+	if len(debates) != 1 || debates[0].Date != "2/1/2021" {
+		t.Errorf("ParseCSVData with SkipBadRows = %+v, want only the 2/1/2021 debate", debates)
+	}
+
+	if len(warnings) != 1 || warnings[0].Kind != RaggedRow {
+		t.Errorf("warnings = %v, want one ragged row warning", warnings)
+	}
+}
+
+// TestParseCSVDataDateColIndex checks that the zero value keeps the name match and a set index overrides it
+func TestParseCSVDataDateColIndex(t *testing.T) {
+
+	data := [][]string{
+		{"Candidate A [1]", "Date", "When"},
+		{"Economy", "1/1/2021", "3/1/2021"},
+	}
+
+	debates, err := ParseCSVData(data, ParseOptions{})
+
+	if err != nil {
+		t.Fatalf("ParseCSVData: %v", err)
+	}
+
+	if debates[0].Date != "1/1/2021" {
+		t.Errorf("date by name = %q, want %q", debates[0].Date, "1/1/2021")
+	}
+
+	index := 2
+
+	if debates, err = ParseCSVData(data, ParseOptions{DateColIndex: &index}); err != nil {
+		t.Fatalf("ParseCSVData with DateColIndex: %v", err)
+	}
+
+	if debates[0].Date != "3/1/2021" {
+		t.Errorf("date by index = %q, want %q", debates[0].Date, "3/1/2021")
+	}
+
+	index = 3
+
+	var dataErr *DataError
+
+	if _, err = ParseCSVData(data, ParseOptions{DateColIndex: &index}); !errors.As(err, &dataErr) || dataErr.Kind != MissingDateColumn {
+		t.Errorf("ParseCSVData with an index past the header: error = %v, want a missing date column DataError", err)
+	}
+}
+
+// generatedRecords is a RecordReader that makes up its rows on demand, like a csv.Reader over a file far too large to
+// hold in memory. Every cell lists a dozen issues, so the input is much larger than the counts parsed from it. atEOF,
+// when set, runs once the last row has been handed out.
+type generatedRecords struct {
+	rows  int
+	next  int
+	atEOF func()
+}
+
+// benchIssues are the issues the generated cells cycle through
+var benchIssues = []string{"Economy", "Jobs", "Healthcare", "Education", "Climate", "Voting Rights"}
+
+// Read implements RecordReader
+func (g *generatedRecords) Read() ([]string, error) {
+	if g.next > g.rows {
+		if g.atEOF != nil {
+			g.atEOF()
+		}
+
+		return nil, io.EOF
+	}
+
+	g.next++
+
+	if g.next == 1 {
+		return []string{"Date", "Candidate A [1]", "Candidate A [2]", "Candidate B [1]", "Candidate B [2]", "Candidate C [1]", "Candidate C [2]"}, nil
+	}
+
+	var row = []string{fmt.Sprintf("%d/%d/2021", g.next%12+1, g.next%28+1)}
+
+	for c := 1; c < 7; c++ {
+		var cell []string
+
+		for i := 0; i < 12; i++ {
+			cell = append(cell, benchIssues[(g.next+c+i)%len(benchIssues)])
+		}
+
+		row = append(row, strings.Join(cell, ", "))
+	}
+
+	return row, nil
+}
+
+// heapAfterGC returns the bytes of live heap objects once garbage has been collected
+func heapAfterGC() uint64 {
+	var stats runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+
+	return stats.HeapAlloc
+}
+
+// benchmarkParse runs parse over inputs of growing size and reports the largest live heap per row seen by the samples
+// parse takes. The streaming path should hold only the parsed counts, a constant amount per row, while reading the
+// whole input first also holds every record.
+func benchmarkParse(b *testing.B, parse func(rows int, sample func()) ([]Debate, error)) {
+
+	for _, rows := range []int{1000, 10000, 100000} {
+		b.Run(fmt.Sprintf("rows=%d", rows), func(b *testing.B) {
+			b.ReportAllocs()
+
+			var before, peak uint64
+
+			sample := func() {
+				b.StopTimer()
+
+				if live := heapAfterGC(); live > before && live-before > peak {
+					peak = live - before
+				}
+
+				b.StartTimer()
+			}
+
+			for n := 0; n < b.N; n++ {
+				b.StopTimer()
+				before = heapAfterGC()
+				b.StartTimer()
+
+				if _, err := parse(rows, sample); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(peak)/float64(rows), "peak-live-B/row")
+		})
+	}
+}
+
+// BenchmarkParseCSV streams generated rows through ParseCSV without ever holding the input
+func BenchmarkParseCSV(b *testing.B) {
+	benchmarkParse(b, func(rows int, sample func()) ([]Debate, error) {
+		debates, err := ParseCSV(&generatedRecords{rows: rows, atEOF: sample}, ParseOptions{})
+		sample()
+
+		return debates, err
+	})
+}
+
+// BenchmarkParseCSVData reads the whole input into memory first, the way ParseCSVData callers do, for comparison with
+// BenchmarkParseCSV
+func BenchmarkParseCSVData(b *testing.B) {
+	benchmarkParse(b, func(rows int, sample func()) ([]Debate, error) {
+		var records [][]string
+		var reader = &generatedRecords{rows: rows}
+
+		for {
+			record, err := reader.Read()
+
+			if err == io.EOF {
+				break
+			}
+
+			records = append(records, record)
+		}
+
+		debates, err := ParseCSVData(records, ParseOptions{})
+		sample()
+		runtime.KeepAlive(records)
+
+		return debates, err
+	})
+}
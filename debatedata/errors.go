@@ -0,0 +1,79 @@
+package debatedata
+
+import "fmt"
+
+// ErrorKind identifies the category of a DataError so callers can react to specific integrity problems
+type ErrorKind int
+
+const (
+	// DuplicateDateColumn means the header contains more than one date column
+	DuplicateDateColumn ErrorKind = iota
+	// MissingDateColumn means the expected date column is not in the header
+	MissingDateColumn
+	// InvalidDate means a debate's date could not be parsed
+	InvalidDate
+	// DisallowedIssue means an issue token matched the disallowed issue pattern
+	DisallowedIssue
+	// InvalidCount means a summary cell that should hold an issue count is not a number
+	InvalidCount
+	// MissingGroupColumn means the column named by ParseOptions.GroupColumn is not in the header
+	MissingGroupColumn
+	// RaggedRow means a data row doesn't have the same number of columns as the header
+	RaggedRow
+	// TotalMismatch means a summary's row, column and grand totals don't reconcile
+	TotalMismatch
+	// EmptyHeader means a candidate column has a blank header, so its issues are counted under an empty name
+	EmptyHeader
+)
+
+// String returns a short human-readable name for the error kind
+func (k ErrorKind) String() string {
+	switch k {
+	case DuplicateDateColumn:
+		return "duplicate date column"
+	case MissingDateColumn:
+		return "missing date column"
+	case InvalidDate:
+		return "invalid date"
+	case DisallowedIssue:
+		return "disallowed issue"
+	case InvalidCount:
+		return "invalid count"
+	case MissingGroupColumn:
+		return "missing group column"
+	case RaggedRow:
+		return "ragged row"
+	case TotalMismatch:
+		return "total mismatch"
+	case EmptyHeader:
+		return "empty header"
+	default:
+		return fmt.Sprintf("unknown error kind %d", int(k))
+	}
+}
+
+// DataError describes a data integrity problem found while parsing or summarizing debate data.
+// Row and Column are zero-based indices into the CSV records (row 0 is the header) for parsing errors, or into the
+// summary matrix for summarizing errors. Either is -1 when the problem is not tied to a specific row or column.
+type DataError struct {
+	Row     int
+	Column  int
+	Kind    ErrorKind
+	Message string
+}
+
+// Error implements the error interface
+func (e *DataError) Error() string {
+	var location string
+
+	switch {
+	case e.Row >= 0 && e.Column >= 0:
+		location = fmt.Sprintf(" at row %d, column %d", e.Row, e.Column)
+	case e.Row >= 0:
+		location = fmt.Sprintf(" at row %d", e.Row)
+	case e.Column >= 0:
+		location = fmt.Sprintf(" at column %d", e.Column)
+	}
+
+	return fmt.Sprintf("data integrity error%s (%v): %s", location, e.Kind, e.Message)
+}
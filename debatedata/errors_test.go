@@ -0,0 +1,24 @@
+package debatedata
+
+import "testing"
+
+// TestDataErrorMessage checks that the message names the location only as far as it is known
+func TestDataErrorMessage(t *testing.T) {
+
+	tests := []struct {
+		err  DataError
+		want string
+	}{
+		{DataError{Row: 2, Column: 3, Kind: InvalidCount, Message: "'x' is not a valid issue count"}, "data integrity error at row 2, column 3 (invalid count): 'x' is not a valid issue count"},
+		{DataError{Row: 4, Column: -1, Kind: RaggedRow, Message: "short row"}, "data integrity error at row 4 (ragged row): short row"},
+		{DataError{Row: -1, Column: 1, Kind: TotalMismatch, Message: "off by one"}, "data integrity error at column 1 (total mismatch): off by one"},
+		{DataError{Row: -1, Column: -1, Kind: MissingDateColumn, Message: "no date"}, "data integrity error (missing date column): no date"},
+		{DataError{Row: -1, Column: -1, Kind: ErrorKind(99), Message: "odd"}, "data integrity error (unknown error kind 99): odd"},
+	}
+
+	for _, test := range tests {
+		if got := test.err.Error(); got != test.want {
+			t.Errorf("Error() = %q, want %q", got, test.want)
+		}
+	}
+}
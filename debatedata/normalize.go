@@ -0,0 +1,155 @@
+package debatedata
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// FoldAccents returns the NFC form of a name with its combining accent marks removed, so "José" and "Jose" compare
+// equal
+func FoldAccents(name string) string {
+
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, name)
+
+	if err != nil {
+		return norm.NFC.String(name)
+	}
+
+	return folded
+}
+
+// displayNames maps the accent-folded form of a name to the display form of its first occurrence
+type displayNames map[string]string
+
+// canonical returns the display form for a name: the NFC form of the first variant seen that folds to the same key.
+// Later variants with or without accents resolve to that first form.
+func (d displayNames) canonical(name string) string {
+
+	key := FoldAccents(name)
+
+	if display, exists := d[key]; exists {
+		return display
+	}
+
+	d[key] = norm.NFC.String(name)
+
+	return d[key]
+}
+
+// Case canonicalization modes choose the display form of issues merged by ParseOptions.IgnoreIssueCase
+const (
+	// CaseCanonicalFirst keeps the casing of the first occurrence
+	CaseCanonicalFirst = "first"
+	// CaseCanonicalMostCommon keeps the casing seen most often, with ties going to the first occurrence
+	CaseCanonicalMostCommon = "most-common"
+)
+
+// issueCasing tracks every casing of each case-folded issue name so a display form can be chosen once all of the
+// source data has been seen
+type issueCasing struct {
+	mode string
+	// forms holds each variant of a key in first-seen order, with counts holding how often each appeared
+	forms  map[string][]string
+	counts map[string]map[string]int
+}
+
+func newIssueCasing(mode string) *issueCasing {
+	return &issueCasing{mode: mode, forms: make(map[string][]string), counts: make(map[string]map[string]int)}
+}
+
+// key records an occurrence of an issue name and returns the case-folded key it merges under
+func (c *issueCasing) key(issue string) string {
+
+	key := strings.ToLower(issue)
+
+	if c.counts[key] == nil {
+		c.counts[key] = make(map[string]int)
+	}
+
+	if c.counts[key][issue] == 0 {
+		c.forms[key] = append(c.forms[key], issue)
+	}
+
+	c.counts[key][issue]++
+
+	return key
+}
+
+// display returns the display form chosen for a case-folded key
+func (c *issueCasing) display(key string) string {
+
+	forms := c.forms[key]
+
+	if len(forms) == 0 {
+		return key
+	}
+
+	display := forms[0]
+
+	if c.mode == CaseCanonicalMostCommon {
+		for _, form := range forms[1:] {
+			if c.counts[key][form] > c.counts[key][display] {
+				display = form
+			}
+		}
+	}
+
+	return display
+}
+
+// apply renames every candidate's case-folded issue keys to their display forms
+func (c *issueCasing) apply(debates []Debate) {
+
+	for k := range debates {
+		for j := range debates[k].Candidates {
+			candidate := &debates[k].Candidates[j]
+
+			issueCount := make(map[string]int)
+
+			for key, count := range candidate.IssueCount {
+				issueCount[c.display(key)] = count
+			}
+
+			candidate.IssueCount = issueCount
+
+			for _, cell := range candidate.CellIssues {
+				for i, key := range cell {
+					cell[i] = c.display(key)
+				}
+			}
+
+			for round, counts := range candidate.RoundCounts {
+				roundCounts := make(map[string]int)
+
+				for key, count := range counts {
+					roundCounts[c.display(key)] = count
+				}
+
+				candidate.RoundCounts[round] = roundCounts
+			}
+
+			if candidate.WeightedCount == nil {
+				continue
+			}
+
+			weightedCount := make(map[string]float64)
+
+			for key, count := range candidate.WeightedCount {
+				weightedCount[c.display(key)] = count
+			}
+
+			candidate.WeightedCount = weightedCount
+		}
+	}
+}
+
+// AliasKey is the form issue names are matched in against the alias lookup: lowercased, with runs of whitespace
+// collapsed to a single space
+func AliasKey(issue string) string {
+	return strings.ToLower(strings.Join(strings.Fields(issue), " "))
+}
@@ -0,0 +1,456 @@
+package debatedata
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SummaryOptions controls how Summarize builds the output matrix
+type SummaryOptions struct {
+	// Cumulative replaces each count with the candidate's running total across the (date-ordered) debates
+	Cumulative bool
+	// Weighted renders each candidate's WeightedCount instead of the integer IssueCount
+	Weighted bool
+	// Sparse drops issue columns whose grand total is zero
+	Sparse bool
+	// Percent renders each cell as its percentage of the row's total instead of a count
+	Percent bool
+	// RowTotals adds a trailing Total column with each row's sum across the issue columns
+	RowTotals bool
+	// GroupColumn names the metadata column carried through between Date and Candidate. Empty omits it.
+	GroupColumn string
+	// MinTotal drops issues whose grand total across every candidate and debate is below it. 0 keeps every issue.
+	MinTotal int
+	// FlattenRounds gives each issue a column per round it was raised in, labeled like "Economy (R2)", filled from
+	// Candidate.RoundCounts instead of the aggregated counts. The debates must be parsed with ParseOptions.KeepRounds.
+	FlattenRounds bool
+	// DebateSubtotals adds a Subtotal row after each debate's candidate rows, summing that debate's counts
+	DebateSubtotals bool
+	// ExcludeIssues names issues, matched case-insensitively, that get no column and count toward no total
+	ExcludeIssues []string
+}
+
+// Summarize builds the output matrix from parsed debate data: a header, one row per candidate per debate, and a
+// final Total row summing each issue column. It reports IssueCount as parsed, so whether a cell holds mentions or
+// rounds is decided by ParseOptions.CountMode; mentions is the default.
+func Summarize(debates *[]Debate, opts SummaryOptions) ([][]string, error) {
+
+	// Create a slice of string slices to be used by the CSV Writer
+	var rows [][]string
+
+	sortedIssues := GetIssues(debates)
+
+	if len(opts.ExcludeIssues) > 0 {
+		sortedIssues = withoutIssues(sortedIssues, opts.ExcludeIssues)
+	}
+
+	if opts.MinTotal > 0 {
+		sortedIssues = frequentIssues(debates, sortedIssues, float64(opts.MinTotal), opts.Weighted)
+	}
+
+	// Each issue column reads one round's counts when rounds are flattened
+	var columns []roundColumn
+	var labels = sortedIssues
+
+	if opts.FlattenRounds {
+		columns = roundColumns(debates, sortedIssues)
+		labels = make([]string, len(columns))
+
+		for k, column := range columns {
+			labels[k] = column.label()
+		}
+	}
+
+	// Build the header based on collection of issues discussed in each debate
+	header := append(KeyColumns(opts.GroupColumn), labels...)
+	first := FirstIssueColumn(header)
+
+	// add the header to the CSV
+	rows = append(rows, header)
+
+	var debateEnds []int
+
+	// iterate the debates and each candidate
+	for _, debate := range *debates {
+
+		for _, candidate := range debate.Candidates {
+
+			// create a new row for each candidate
+			var row = make([]string, len(header))
+
+			for hk, h := range header {
+
+				switch {
+				case hk == 0:
+					row[hk] = debate.Date
+				case hk == first-1:
+					row[hk] = candidate.Name
+				case hk < first:
+					row[hk] = debate.Group
+				case opts.FlattenRounds:
+					column := columns[hk-first]
+					row[hk] = strconv.Itoa(candidate.RoundCounts[column.round][column.issue])
+				default:
+					if opts.Weighted {
+						row[hk] = FormatWeighted(candidate.WeightedCount[h])
+					} else {
+						row[hk] = strconv.Itoa(candidate.IssueCount[h])
+					}
+				}
+			}
+
+			// add each row to the CSV
+			rows = append(rows, row)
+
+		}
+
+		// Remember where each debate's rows end, for the subtotal rows
+		debateEnds = append(debateEnds, len(rows))
+
+	}
+
+	// Create a final row -- this is used to summarize each issue category
+	var finalRow = make([]string, len(header))
+
+	finalRow[first-1] = "Total"
+
+	// Start after the key columns (date, any group column, and candidate)
+	// Get the length of the header row so you know how many columns to expect
+	for colNum := first; colNum < len(rows[0]); colNum++ {
+		var total = 0.0
+
+		// Start 1 row down, because the first row is the header
+		for rowNum := 1; rowNum < len(rows); rowNum++ {
+			val, err := parseCount(rows[rowNum][colNum], opts.Weighted)
+
+			if err != nil {
+				return nil, &DataError{
+					Row:     rowNum,
+					Column:  colNum,
+					Kind:    InvalidCount,
+					Message: fmt.Sprintf("'%v' is not a valid issue count", rows[rowNum][colNum]),
+				}
+			}
+
+			// Calculate the running total for each row in the given column
+			total += val
+		}
+
+		// Add the total to the final row in the appropriate column
+		finalRow[colNum] = FormatWeighted(total)
+	}
+
+	// The Total column sums each row's issue cells, so in the Total row it holds the grand total
+	if opts.RowTotals {
+		rows[0] = append(rows[0], "Total")
+
+		for rowNum := 1; rowNum < len(rows); rowNum++ {
+			rows[rowNum] = withRowTotal(rows[rowNum], first)
+		}
+
+		finalRow = withRowTotal(finalRow, first)
+	}
+
+	// Subtotals are inserted once the Total row and any row totals are computed, so they are never counted twice
+	if opts.DebateSubtotals {
+		rows = withDebateSubtotals(rows, debateEnds, first)
+	}
+
+	// In cumulative mode each cell becomes the candidate's running total up to and including that debate. This runs
+	// after the Total row is computed so the totals still reflect the per-debate counts.
+	if opts.Cumulative {
+		accumulateRows(rows[1:], first)
+	}
+
+	rows = append(rows, finalRow)
+
+	// Sparse mode runs last so it reflects the final data rather than the raw issue vocabulary
+	if opts.Sparse {
+		rows = dropZeroColumns(rows)
+	}
+
+	if opts.Percent {
+		rowShares(rows[1:], first)
+	}
+
+	return rows, nil
+
+}
+
+// roundColumn is the issue column of a single round in a FlattenRounds summary
+type roundColumn struct {
+	issue string
+	round int
+}
+
+// label is the column's header: the issue with an " (R#)" suffix, or the bare issue for round 0 columns whose title had
+// no round suffix
+func (c roundColumn) label() string {
+	if c.round == 0 {
+		return c.issue
+	}
+
+	return fmt.Sprintf("%v (R%d)", c.issue, c.round)
+}
+
+// roundColumns returns a column for every round each issue was raised in, following the order of issues and then
+// ascending round number
+func roundColumns(debates *[]Debate, issues []string) []roundColumn {
+
+	var rounds = make(map[string]map[int]bool)
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			for round, counts := range candidate.RoundCounts {
+				for issue := range counts {
+					if rounds[issue] == nil {
+						rounds[issue] = make(map[int]bool)
+					}
+
+					rounds[issue][round] = true
+				}
+			}
+		}
+	}
+
+	var columns []roundColumn
+
+	for _, issue := range issues {
+		var numbers []int
+
+		for round := range rounds[issue] {
+			numbers = append(numbers, round)
+		}
+
+		sort.Ints(numbers)
+
+		for _, round := range numbers {
+			columns = append(columns, roundColumn{issue: issue, round: round})
+		}
+	}
+
+	return columns
+}
+
+// frequentIssues returns the issues whose total across all debates reaches min, keeping their order. Weighted totals
+// are compared when weighted is set.
+func frequentIssues(debates *[]Debate, issues []string, min float64, weighted bool) []string {
+
+	var totals = make(map[string]float64)
+
+	for _, debate := range *debates {
+		for _, candidate := range debate.Candidates {
+			for _, issue := range issues {
+				if weighted {
+					totals[issue] += candidate.WeightedCount[issue]
+				} else {
+					totals[issue] += float64(candidate.IssueCount[issue])
+				}
+			}
+		}
+	}
+
+	var kept []string
+
+	for _, issue := range issues {
+		if totals[issue] >= min {
+			kept = append(kept, issue)
+		}
+	}
+
+	return kept
+}
+
+// withoutIssues returns the issues that don't match any of the excluded names, ignoring case, keeping their order
+func withoutIssues(issues []string, excluded []string) []string {
+
+	var kept []string
+
+	for _, issue := range issues {
+		var drop = false
+
+		for _, name := range excluded {
+			if strings.EqualFold(issue, strings.TrimSpace(name)) {
+				drop = true
+				break
+			}
+		}
+
+		if !drop {
+			kept = append(kept, issue)
+		}
+	}
+
+	return kept
+}
+
+// rowShares rewrites the issue columns of each row in place, from column first on, as that cell's percentage of the
+// row's total to one decimal place. For the Total row this is each issue's share of the grand total. A row with no
+// mentions gets 0.0 throughout.
+func rowShares(rows [][]string, first int) {
+
+	for _, row := range rows {
+		var values = make([]float64, len(row))
+		var total = 0.0
+
+		for colNum := first; colNum < len(row); colNum++ {
+			values[colNum], _ = strconv.ParseFloat(row[colNum], 64)
+			total += values[colNum]
+		}
+
+		for colNum := first; colNum < len(row); colNum++ {
+			var share = 0.0
+
+			if total != 0 {
+				share = values[colNum] / total * 100
+			}
+
+			row[colNum] = strconv.FormatFloat(share, 'f', 1, 64)
+		}
+	}
+}
+
+// KeyColumns returns the leading columns of a summary header: Date, the group column when one is named, and Candidate
+func KeyColumns(groupColumn string) []string {
+	if groupColumn == "" {
+		return []string{"Date", "Candidate"}
+	}
+
+	return []string{"Date", groupColumn, "Candidate"}
+}
+
+// FirstIssueColumn returns the index of the first issue column in a summary header, just after the Candidate column
+func FirstIssueColumn(header []string) int {
+	for k, h := range header {
+		if h == "Candidate" {
+			return k + 1
+		}
+	}
+
+	return 2
+}
+
+// withRowTotal returns row with a trailing cell holding the sum of its issue cells, from column first on. The cells
+// must already be valid counts.
+func withRowTotal(row []string, first int) []string {
+	var total = 0.0
+
+	for _, val := range row[first:] {
+		count, _ := strconv.ParseFloat(val, 64)
+		total += count
+	}
+
+	return append(row, FormatWeighted(total))
+}
+
+// subtotalLabel is the Candidate cell of a debate's subtotal row
+const subtotalLabel = "Subtotal"
+
+// withDebateSubtotals returns rows with a Subtotal row after each debate's candidate rows, where ends holds the index
+// just past each debate's last row. A subtotal row keeps the debate's key cells and sums its rows from column first
+// on. The cells must already be valid counts.
+func withDebateSubtotals(rows [][]string, ends []int, first int) [][]string {
+
+	var withSubtotals = [][]string{rows[0]}
+	var start = 1
+
+	for _, end := range ends {
+		withSubtotals = append(withSubtotals, rows[start:end]...)
+
+		if end > start {
+			var subtotal = make([]string, len(rows[start]))
+			copy(subtotal, rows[start][:first])
+			subtotal[first-1] = subtotalLabel
+
+			for colNum := first; colNum < len(subtotal); colNum++ {
+				var total = 0.0
+
+				for _, row := range rows[start:end] {
+					count, _ := strconv.ParseFloat(row[colNum], 64)
+					total += count
+				}
+
+				subtotal[colNum] = FormatWeighted(total)
+			}
+
+			withSubtotals = append(withSubtotals, subtotal)
+		}
+
+		start = end
+	}
+
+	return withSubtotals
+}
+
+// dropZeroColumns removes the issue columns whose value in the final (Total) row is zero
+func dropZeroColumns(rows [][]string) [][]string {
+
+	totals := rows[len(rows)-1]
+	first := FirstIssueColumn(rows[0])
+
+	var keep []int
+
+	for colNum := range totals {
+		// Always keep the key columns
+		if colNum < first {
+			keep = append(keep, colNum)
+			continue
+		}
+
+		if val, err := strconv.ParseFloat(totals[colNum], 64); err != nil || val != 0 {
+			keep = append(keep, colNum)
+		}
+	}
+
+	for rowNum, row := range rows {
+		var kept = make([]string, len(keep))
+
+		for k, colNum := range keep {
+			kept[k] = row[colNum]
+		}
+
+		rows[rowNum] = kept
+	}
+
+	return rows
+}
+
+// parseCount reads a summary cell back as a number. Unweighted cells must be integers; weighted cells may be decimals.
+func parseCount(val string, weighted bool) (float64, error) {
+	if weighted {
+		return strconv.ParseFloat(val, 64)
+	}
+
+	count, err := strconv.Atoi(val)
+
+	return float64(count), err
+}
+
+// FormatWeighted renders a count using the fewest decimal places needed, so whole numbers print as integers
+func FormatWeighted(val float64) string {
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
+
+// accumulateRows rewrites the issue columns of candidate rows in place so each holds the running sum of that
+// candidate's counts over all rows up to and including it. Rows must already be in chronological order. Issue columns
+// start at first; the key columns after Date (any group column and the candidate) identify whose total it is.
+func accumulateRows(rows [][]string, first int) {
+
+	running := make(map[string][]float64)
+
+	for _, row := range rows {
+		name := strings.Join(row[1:first], "\x00")
+
+		if _, exists := running[name]; !exists {
+			running[name] = make([]float64, len(row))
+		}
+
+		for colNum := first; colNum < len(row); colNum++ {
+			val, _ := strconv.ParseFloat(row[colNum], 64)
+			running[name][colNum] += val
+			row[colNum] = FormatWeighted(running[name][colNum])
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package debatedata
+
+import (
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// update rewrites the golden files from the current output: go test ./debatedata -update
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// debatesFixture is a realistic input: two debates, three candidates and two rounds each, with an empty cell and an
+// issue repeated within a cell
+const debatesFixture = `Date,Candidate A [1],Candidate A [2],Candidate B [1],Candidate B [2],Candidate C [1],Candidate C [2]
+1/1/2021,"Economy, Jobs, Environment",Education,Healthcare,"Healthcare, Jobs",Voting Rights,
+6/1/2021,Democracy,"Economy, Economy",Foreign Policy,"Jobs, Minimum Wage","Healthcare, Education",Environment
+`
+
+// TestSummarizeDeterministic checks that parsing and summarizing the same input twice produces identical output
+func TestSummarizeDeterministic(t *testing.T) {
+
+	data := [][]string{
+		{"Date", "Candidate A [1]", "Candidate A [2]", "Candidate B [1]", "Candidate B [2]"},
+		{"1/1/2021", "Jobs, economy", "Voting Rights", "Economy, abortion", "Healthcare, jobs"},
+		{"2/1/2021", "Democracy", "Zoning, Jobs", "Education", "Climate, Economy"},
+	}
+
+	var runs [2][][]string
+
+	for run := range runs {
+		debates, err := ParseCSVData(data, ParseOptions{})
+
+		if err != nil {
+			t.Fatalf("run %d: ParseCSVData: %v", run, err)
+		}
+
+		if runs[run], err = Summarize(&debates, SummaryOptions{}); err != nil {
+			t.Fatalf("run %d: Summarize: %v", run, err)
+		}
+	}
+
+	if !reflect.DeepEqual(runs[0], runs[1]) {
+		t.Errorf("runs differ:\n%q\n%q", runs[0], runs[1])
+	}
+
+	wantHeader := []string{"Date", "Candidate", "abortion", "Climate", "Democracy", "Economy", "economy", "Education", "Healthcare", "Jobs", "jobs", "Voting Rights", "Zoning"}
+
+	if !reflect.DeepEqual(runs[0][0], wantHeader) {
+		t.Errorf("header = %q, want %q", runs[0][0], wantHeader)
+	}
+}
+
+// TestSummarizeTotals checks the Total row and Total column math against the cells they sum
+func TestSummarizeTotals(t *testing.T) {
+
+	tests := []struct {
+		name string
+		opts SummaryOptions
+		// want is the expected Total row
+		want []string
+	}{
+		{"column totals", SummaryOptions{}, []string{"", "Total", "1", "3", "2", "2", "1", "3", "3", "1", "1"}},
+		{"with row totals", SummaryOptions{RowTotals: true}, []string{"", "Total", "1", "3", "2", "2", "1", "3", "3", "1", "1", "17"}},
+		{"cumulative keeps per-debate totals", SummaryOptions{Cumulative: true}, []string{"", "Total", "1", "3", "2", "2", "1", "3", "3", "1", "1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			debates, err := ParseCSVData(readFixture(t, debatesFixture), ParseOptions{})
+
+			if err != nil {
+				t.Fatalf("ParseCSVData: %v", err)
+			}
+
+			summary, err := Summarize(&debates, test.opts)
+
+			if err != nil {
+				t.Fatalf("Summarize: %v", err)
+			}
+
+			if got := summary[len(summary)-1]; !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Total row = %q, want %q", got, test.want)
+			}
+
+			// Each candidate row's Total is the sum of its issue cells
+			if test.opts.RowTotals {
+				for _, row := range summary[1 : len(summary)-1] {
+					var sum = 0
+
+					for _, cell := range row[2 : len(row)-1] {
+						n, _ := parseCount(cell, false)
+						sum += int(n)
+					}
+
+					if FormatWeighted(float64(sum)) != row[len(row)-1] {
+						t.Errorf("row %q: Total %v, want %d", row[:2], row[len(row)-1], sum)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestSummarizeGolden compares the summary of debatesFixture with testdata/summary.golden
+func TestSummarizeGolden(t *testing.T) {
+
+	debates, err := ParseCSVData(readFixture(t, debatesFixture), ParseOptions{})
+
+	if err != nil {
+		t.Fatalf("ParseCSVData: %v", err)
+	}
+
+	summary, err := Summarize(&debates, SummaryOptions{})
+
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+
+	var got bytes.Buffer
+
+	if err = csv.NewWriter(&got).WriteAll(summary); err != nil {
+		t.Fatalf("writing summary: %v", err)
+	}
+
+	golden := filepath.Join("testdata", "summary.golden")
+
+	if *update {
+		if err = os.WriteFile(golden, got.Bytes(), 0644); err != nil {
+			t.Fatalf("updating golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("summary differs from %v:\n%s\nwant:\n%s", golden, got.Bytes(), want)
+	}
+}
+
+// TestSummarizeOptions checks the first candidate row under the options that reshape the matrix
+func TestSummarizeOptions(t *testing.T) {
+
+	data := [][]string{
+		{"Date", "Region", "Candidate A [1]", "Candidate A [2]", "Candidate B [1]"},
+		{"1/1/2021", "North", "Economy, Jobs", "Economy", "Climate"},
+	}
+
+	tests := []struct {
+		name       string
+		parse      ParseOptions
+		opts       SummaryOptions
+		wantHeader []string
+		wantRow    []string
+	}{
+		{"default", ParseOptions{GroupColumn: "Region"}, SummaryOptions{},
+			[]string{"Date", "Candidate", "Climate", "Economy", "Jobs"}, []string{"1/1/2021", "Candidate A", "0", "2", "1"}},
+		{"group column", ParseOptions{GroupColumn: "Region"}, SummaryOptions{GroupColumn: "Region"},
+			[]string{"Date", "Region", "Candidate", "Climate", "Economy", "Jobs"}, []string{"1/1/2021", "North", "Candidate A", "0", "2", "1"}},
+		{"percent", ParseOptions{GroupColumn: "Region"}, SummaryOptions{Percent: true},
+			[]string{"Date", "Candidate", "Climate", "Economy", "Jobs"}, []string{"1/1/2021", "Candidate A", "0.0", "66.7", "33.3"}},
+		{"min total", ParseOptions{GroupColumn: "Region"}, SummaryOptions{MinTotal: 2},
+			[]string{"Date", "Candidate", "Economy"}, []string{"1/1/2021", "Candidate A", "2"}},
+		{"exclude", ParseOptions{GroupColumn: "Region"}, SummaryOptions{ExcludeIssues: []string{"economy"}},
+			[]string{"Date", "Candidate", "Climate", "Jobs"}, []string{"1/1/2021", "Candidate A", "0", "1"}},
+		{"flatten rounds", ParseOptions{GroupColumn: "Region", KeepRounds: true}, SummaryOptions{FlattenRounds: true},
+			[]string{"Date", "Candidate", "Climate (R1)", "Economy (R1)", "Economy (R2)", "Jobs (R1)"}, []string{"1/1/2021", "Candidate A", "0", "1", "1", "1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			debates, err := ParseCSVData(data, test.parse)
+
+			if err != nil {
+				t.Fatalf("ParseCSVData: %v", err)
+			}
+
+			summary, err := Summarize(&debates, test.opts)
+
+			if err != nil {
+				t.Fatalf("Summarize: %v", err)
+			}
+
+			if !reflect.DeepEqual(summary[0], test.wantHeader) {
+				t.Errorf("header = %q, want %q", summary[0], test.wantHeader)
+			}
+
+			if !reflect.DeepEqual(summary[1], test.wantRow) {
+				t.Errorf("first row = %q, want %q", summary[1], test.wantRow)
+			}
+
+			if first := FirstIssueColumn(summary[0]); first != len(KeyColumns(test.opts.GroupColumn)) {
+				t.Errorf("FirstIssueColumn = %d, want %d", first, len(KeyColumns(test.opts.GroupColumn)))
+			}
+		})
+	}
+}